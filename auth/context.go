@@ -0,0 +1,34 @@
+package auth
+
+import "context"
+
+// Principal adalah user yang sedang login, diambil dari access token.
+type Principal struct {
+	UserId   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Can bernilai true jika role user memiliki permission.
+func (p Principal) Can(permission Permission) bool {
+	return Can(p.Role, permission)
+}
+
+// Owns bernilai true jika authorId adalah user ini.
+func (p Principal) Owns(authorId *uint) bool {
+	return authorId != nil && *authorId == p.UserId
+}
+
+type principalKey struct{}
+
+// NewContext menyimpan principal pada context request.
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext mengambil principal dari context. ok bernilai false jika
+// request tidak membawa token.
+func FromContext(ctx context.Context) (principal Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,56 @@
+// Package auth berisi hashing password, penerbitan dan verifikasi JWT, serta
+// identitas user yang sedang login pada context request.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength dalam byte; bcrypt mengabaikan byte setelah 72
+	MaxPasswordLength = 72
+)
+
+var ErrInvalidPassword = errors.New("invalid password")
+
+// ValidatePassword memeriksa panjang password sebelum di-hash.
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrInvalidPassword, MinPasswordLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return fmt.Errorf("%w: must be at most %d bytes", ErrInvalidPassword, MaxPasswordLength)
+	}
+	return nil
+}
+
+// HashPassword membuat hash bcrypt dari password.
+func HashPassword(password string) (string, error) {
+	if err := ValidatePassword(password); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword bernilai true jika password cocok dengan hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyHash dipakai saat user tidak ditemukan, supaya waktu respons login
+// tidak membocorkan username mana yang terdaftar.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("recipebook-dummy-password"), bcrypt.DefaultCost)
+
+// CheckDummyPassword menjalankan perbandingan bcrypt yang selalu gagal.
+func CheckDummyPassword(password string) {
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
@@ -0,0 +1,61 @@
+package auth
+
+import "go-rest-modul/models"
+
+// Permission adalah hak akses yang diperiksa saat route didaftarkan.
+type Permission string
+
+const (
+	// PermRecipeWrite: membuat recipe dan mengubah/menghapus recipe sendiri
+	PermRecipeWrite Permission = "recipe:write"
+	// PermRecipeModerate: mengubah/menghapus recipe milik siapa pun
+	PermRecipeModerate   Permission = "recipe:moderate"
+	PermCategoryWrite    Permission = "category:write"
+	PermCategoryDelete   Permission = "category:delete"
+	PermIngredientWrite  Permission = "ingredient:write"
+	PermIngredientDelete Permission = "ingredient:delete"
+	PermTagWrite         Permission = "tag:write"
+	PermTagDelete        Permission = "tag:delete"
+	PermCatalogImport    Permission = "catalog:import"
+	PermUserManage       Permission = "user:manage"
+)
+
+// rolePermissions memetakan role ke permission yang dimilikinya. Viewer
+// hanya bisa membaca dan mengelola data pribadinya.
+var rolePermissions = map[string][]Permission{
+	models.RoleViewer: {},
+	models.RoleEditor: {
+		PermRecipeWrite,
+		PermCategoryWrite,
+		PermIngredientWrite,
+		PermTagWrite,
+	},
+	models.RoleAdmin: {
+		PermRecipeWrite,
+		PermRecipeModerate,
+		PermCategoryWrite,
+		PermCategoryDelete,
+		PermIngredientWrite,
+		PermIngredientDelete,
+		PermTagWrite,
+		PermTagDelete,
+		PermCatalogImport,
+		PermUserManage,
+	},
+}
+
+// ValidRole bernilai true jika role dikenal.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can bernilai true jika role memiliki permission.
+func Can(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-rest-modul/config"
+	"go-rest-modul/models"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Jenis token pada claim typ.
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims adalah isi JWT. Subject berisi ID user dan ID berisi ID session
+// (hanya untuk refresh token). Role baru berlaku setelah token di-refresh.
+type Claims struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Type     string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// UserId membaca ID user dari subject.
+func (c Claims) UserId() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	return uint(id), nil
+}
+
+// Pair adalah pasangan token yang dikirim ke client setelah login/refresh.
+type Pair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"` // detik
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// Tokens menerbitkan dan memverifikasi JWT yang ditandatangani HMAC-SHA256.
+type Tokens struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokens(cfg config.AuthConfig) *Tokens {
+	return &Tokens{
+		secret:     []byte(cfg.Secret),
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+	}
+}
+
+// RandomSecret membuat kunci acak untuk dipakai jika auth.secret kosong.
+func RandomSecret() string {
+	return NewSessionId() + NewSessionId()
+}
+
+// NewSessionId membuat ID acak untuk claim jti refresh token.
+func NewSessionId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// RefreshExpiry adalah waktu kedaluwarsa refresh token yang diterbitkan now.
+func (t *Tokens) RefreshExpiry(now time.Time) time.Time {
+	return now.Add(t.refreshTTL).Truncate(time.Second)
+}
+
+// Issue menerbitkan access token dan refresh token untuk user. sessionId
+// dipakai sebagai jti refresh token dan harus disimpan oleh pemanggil.
+func (t *Tokens) Issue(user models.User, sessionId string, now time.Time) (Pair, error) {
+	access, err := t.sign(user, TokenAccess, "", now, now.Add(t.accessTTL))
+	if err != nil {
+		return Pair{}, err
+	}
+	refreshExpiry := t.RefreshExpiry(now)
+	refresh, err := t.sign(user, TokenRefresh, sessionId, now, refreshExpiry)
+	if err != nil {
+		return Pair{}, err
+	}
+	return Pair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(t.accessTTL / time.Second),
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiry,
+	}, nil
+}
+
+func (t *Tokens) sign(user models.User, tokenType, id string, now, expiry time.Time) (string, error) {
+	claims := Claims{
+		Username: user.Username,
+		Role:     user.Role,
+		Type:     tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    t.issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			ID:        id,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiry),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+}
+
+// Parse memverifikasi tanda tangan, issuer, masa berlaku dan jenis token.
+func (t *Tokens) Parse(token, tokenType string) (Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(t.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Type != tokenType {
+		return Claims{}, fmt.Errorf("%w: expected %s token", ErrInvalidToken, tokenType)
+	}
+	if _, err := claims.UserId(); err != nil {
+		return Claims{}, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,214 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Columns adalah urutan kolom CSV hasil ekspor. Saat impor urutan kolom
+// bebas dan kolom selain type boleh tidak ada.
+var Columns = []string{
+	"type", "category", "ingredient", "density", "recipe", "descriptions",
+	"instructions", "prep_time", "cook_time", "servings", "image_url", "amount", "unit",
+}
+
+// Read membaca semua record dari r. Baris yang tidak bisa dibaca dilaporkan
+// lewat rowErrors dan dilewati; err hanya untuk kesalahan yang membuat file
+// tidak bisa dibaca sama sekali, misal header CSV tanpa kolom type.
+func Read(r io.Reader, format string) (records []Record, rowErrors []RowError, err error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatNDJSON:
+		return readNDJSON(r)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func readCSV(r io.Reader) ([]Record, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("csv header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index[name] = i
+	}
+	if _, ok := index["type"]; !ok {
+		return nil, nil, errors.New("csv header: missing column \"type\"")
+	}
+
+	var records []Record
+	var rowErrors []RowError
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		// Nomor baris di file (header adalah baris 1), sama seperti di
+		// spreadsheet selama tidak ada sel berisi beberapa baris
+		row, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			if i, ok := index[column]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		record := Record{
+			Row:          row,
+			Type:         strings.ToLower(get("type")),
+			Category:     get("category"),
+			Ingredient:   get("ingredient"),
+			Recipe:       get("recipe"),
+			Descriptions: get("descriptions"),
+			Instructions: get("instructions"),
+			ImageURL:     get("image_url"),
+			Amount:       get("amount"),
+			Unit:         get("unit"),
+		}
+		// Baris kosong di spreadsheet diabaikan
+		if record.Type == "" && strings.TrimSpace(strings.Join(fields, "")) == "" {
+			continue
+		}
+
+		var problems []string
+		if v := get("density"); v != "" {
+			f, err := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+			if err != nil {
+				problems = append(problems, "density must be a number")
+			}
+			record.Density = &f
+		}
+		for _, field := range []struct {
+			column string
+			target **int
+		}{
+			{"prep_time", &record.PrepTime},
+			{"cook_time", &record.CookTime},
+			{"servings", &record.Servings},
+		} {
+			if v := get(field.column); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					problems = append(problems, field.column+" must be a whole number")
+				}
+				*field.target = &n
+			}
+		}
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, NewRowError(record, "%s", strings.Join(problems, "; ")))
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, rowErrors, nil
+}
+
+func readNDJSON(r io.Reader) ([]Record, []RowError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	var records []Record
+	var rowErrors []RowError
+	for row := 1; scanner.Scan(); row++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var record Record
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			rowErrors = append(rowErrors, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+		record.Row = row
+		record.Type = strings.ToLower(strings.TrimSpace(record.Type))
+		records = append(records, record)
+	}
+	return records, rowErrors, scanner.Err()
+}
+
+// Writer menulis record satu per satu sehingga ekspor bisa di-stream.
+type Writer interface {
+	Write(record Record) error
+	// Flush harus dipanggil setelah record terakhir.
+	Flush() error
+}
+
+// NewWriter membuat Writer untuk format. Writer CSV langsung menulis header.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		writer := &csvWriter{w: csv.NewWriter(w)}
+		return writer, writer.w.Write(Columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(record Record) error {
+	formatInt := func(v *int) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	}
+	density := ""
+	if record.Density != nil {
+		density = strconv.FormatFloat(*record.Density, 'f', -1, 64)
+	}
+	return c.w.Write([]string{
+		record.Type, record.Category, record.Ingredient, density, record.Recipe, record.Descriptions,
+		record.Instructions, formatInt(record.PrepTime), formatInt(record.CookTime), formatInt(record.Servings),
+		record.ImageURL, record.Amount, record.Unit,
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := n.w.Write(data); err != nil {
+		return err
+	}
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
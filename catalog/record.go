@@ -0,0 +1,136 @@
+// Package catalog berisi format baris untuk impor dan ekspor seluruh katalog
+// (category, ingredient, recipe dan baris bahan) sebagai CSV atau NDJSON.
+//
+// Setiap baris punya kolom type yang menentukan kolom lain yang dipakai:
+//
+//	category    category
+//	ingredient  ingredient, density
+//	recipe      recipe, category, descriptions, instructions, prep_time,
+//	            cook_time, servings, image_url
+//	line        recipe, ingredient, amount, unit
+//
+// Baris line milik satu recipe mengganti seluruh baris bahan recipe itu,
+// sesuai urutan kemunculannya.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Jenis baris pada kolom type.
+const (
+	TypeCategory   = "category"
+	TypeIngredient = "ingredient"
+	TypeRecipe     = "recipe"
+	TypeLine       = "line"
+)
+
+// Format file yang didukung.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("unknown catalog format")
+
+// ParseFormat membaca nama format, termasuk alias seperti "jsonl".
+func ParseFormat(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "csv":
+		return FormatCSV, nil
+	case "ndjson", "jsonl", "json":
+		return FormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// Record adalah satu baris katalog. Field kosong/nil pada baris recipe dan
+// ingredient yang sudah ada berarti nilainya tidak diubah.
+type Record struct {
+	// Row adalah nomor baris pada file, dipakai untuk laporan error
+	Row          int      `json:"-"`
+	Type         string   `json:"type"`
+	Category     string   `json:"category,omitempty"`
+	Ingredient   string   `json:"ingredient,omitempty"`
+	Density      *float64 `json:"density,omitempty"`
+	Recipe       string   `json:"recipe,omitempty"`
+	Descriptions string   `json:"descriptions,omitempty"`
+	Instructions string   `json:"instructions,omitempty"`
+	PrepTime     *int     `json:"prep_time,omitempty"`
+	CookTime     *int     `json:"cook_time,omitempty"`
+	Servings     *int     `json:"servings,omitempty"`
+	ImageURL     string   `json:"image_url,omitempty"`
+	Amount       string   `json:"amount,omitempty"`
+	Unit         string   `json:"unit,omitempty"`
+}
+
+// Key adalah natural key baris: nama category/ingredient atau judul recipe.
+func (r Record) Key() string {
+	switch r.Type {
+	case TypeCategory:
+		return r.Category
+	case TypeIngredient:
+		return r.Ingredient
+	default:
+		return r.Recipe
+	}
+}
+
+// RowError adalah error pada satu baris file.
+type RowError struct {
+	Row     int    `json:"row"`
+	Type    string `json:"type,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+}
+
+// NewRowError membuat RowError untuk record.
+func NewRowError(record Record, format string, args ...interface{}) RowError {
+	return RowError{
+		Row:     record.Row,
+		Type:    record.Type,
+		Key:     record.Key(),
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Counts adalah jumlah data yang dibuat dan diubah per jenis.
+type Counts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// Report adalah hasil impor. Committed bernilai false pada dry run atau jika
+// ada error, dan pada kondisi itu tidak ada perubahan yang disimpan.
+type Report struct {
+	Rows        int        `json:"rows"`
+	DryRun      bool       `json:"dry_run"`
+	Committed   bool       `json:"committed"`
+	Categories  Counts     `json:"categories"`
+	Ingredients Counts     `json:"ingredients"`
+	Recipes     Counts     `json:"recipes"`
+	Lines       int        `json:"lines"`
+	Errors      []RowError `json:"errors"`
+}
+
+// AddReadErrors menambahkan error dari Read ke laporan. Baris yang gagal
+// dibaca tetap dihitung di Rows dan error diurutkan menurut nomor baris.
+func (r *Report) AddReadErrors(errs []RowError) {
+	if len(errs) == 0 {
+		return
+	}
+	r.Rows += len(errs)
+	r.Committed = false
+	r.Errors = append(r.Errors, errs...)
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		return r.Errors[i].Row < r.Errors[j].Row
+	})
+}
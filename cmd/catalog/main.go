@@ -0,0 +1,152 @@
+// Command catalog mengimpor dan mengekspor seluruh katalog sebagai CSV atau
+// NDJSON, dengan format yang sama seperti /api/catalog/import dan
+// /api/catalog/export.
+//
+//	go run ./cmd/catalog import -dry-run katalog.csv
+//	go run ./cmd/catalog export -format ndjson > katalog.ndjson
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go-rest-modul/catalog"
+	"go-rest-modul/config"
+	"go-rest-modul/database"
+	"go-rest-modul/repository"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Penggunaan:")
+	fmt.Fprintln(os.Stderr, "  catalog import [-format csv|ndjson] [-dry-run] FILE")
+	fmt.Fprintln(os.Stderr, "  catalog export [-format csv|ndjson] [-o FILE]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	configPath := flags.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "path ke file konfigurasi YAML/TOML (opsional)")
+	formatName := flags.String("format", "", "csv atau ndjson (default dari ekstensi file, lalu csv)")
+
+	switch os.Args[1] {
+	case "import":
+		dryRun := flags.Bool("dry-run", false, "periksa file tanpa menyimpan")
+		flags.Parse(os.Args[2:])
+		if flags.NArg() != 1 {
+			usage()
+		}
+		repo := connect(*configPath)
+		os.Exit(runImport(repo, flags.Arg(0), *formatName, *dryRun))
+	case "export":
+		output := flags.String("o", "", "file tujuan (default stdout)")
+		flags.Parse(os.Args[2:])
+		repo := connect(*configPath)
+		runExport(repo, *output, *formatName)
+	default:
+		usage()
+	}
+}
+
+func connect(configPath string) repository.CatalogRepository {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatal("Konfigurasi tidak valid: ", err)
+	}
+	db, err := database.Connect(cfg.Database, cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return repository.NewCatalogRepository(db)
+}
+
+// resolveFormat memakai -format jika diisi, jika tidak dari ekstensi file.
+func resolveFormat(name, path string) string {
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if name == "" {
+		return catalog.FormatCSV
+	}
+	format, err := catalog.ParseFormat(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return format
+}
+
+// runImport mencetak laporan sebagai JSON ke stdout dan mengembalikan exit
+// code 1 jika ada baris yang error.
+func runImport(repo repository.CatalogRepository, path, formatName string, dryRun bool) int {
+	format := resolveFormat(formatName, path)
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	records, readErrors, err := catalog.Read(input, format)
+	if err != nil {
+		log.Fatal("Gagal membaca file: ", err)
+	}
+	report, err := repo.Import(context.Background(), records, 0, dryRun || len(readErrors) > 0)
+	if err != nil {
+		log.Fatal("Gagal mengimpor katalog: ", err)
+	}
+	report.DryRun = dryRun
+	report.AddReadErrors(readErrors)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if len(report.Errors) > 0 {
+		log.Printf("%d baris error, tidak ada perubahan yang disimpan", len(report.Errors))
+		return 1
+	}
+	if dryRun {
+		log.Println("Dry run selesai, tidak ada perubahan yang disimpan")
+	} else {
+		log.Printf("Selesai: %d baris diimpor", report.Rows)
+	}
+	return 0
+}
+
+func runExport(repo repository.CatalogRepository, path, formatName string) {
+	format := resolveFormat(formatName, path)
+
+	var output io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	writer, err := catalog.NewWriter(output, format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := repo.Export(context.Background(), writer.Write); err != nil {
+		log.Fatal("Gagal mengekspor katalog: ", err)
+	}
+	if err := writer.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
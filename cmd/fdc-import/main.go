@@ -0,0 +1,100 @@
+// Command fdc-import mengisi tabel gizi bahan dari dump CSV USDA FoodData
+// Central (https://fdc.nal.usda.gov/download-datasets). Folder dump harus
+// berisi food.csv dan food_nutrient.csv.
+//
+//	go run ./cmd/fdc-import -dir ./FoodData_Central_csv
+package main
+
+import (
+	"context"
+	"flag"
+	"go-rest-modul/config"
+	"go-rest-modul/database"
+	"go-rest-modul/models"
+	"go-rest-modul/nutrition"
+	"go-rest-modul/repository"
+	"log"
+	"os"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "path ke file konfigurasi YAML/TOML (opsional)")
+	dir := flag.String("dir", "", "folder dump CSV FoodData Central")
+	overwrite := flag.Bool("overwrite", false, "timpa data gizi yang sudah ada")
+	dryRun := flag.Bool("dry-run", false, "tampilkan kecocokan tanpa menyimpan")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("Parameter -dir wajib diisi")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Konfigurasi tidak valid: ", err)
+	}
+	db, err := database.Connect(cfg.Database, cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ingredients := repository.NewIngredientRepository(db)
+	nutrients := repository.NewNutrientRepository(db)
+
+	all, err := ingredients.List(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Lewati bahan yang sudah punya data gizi, kecuali diminta menimpa
+	ids := make([]uint, len(all))
+	for i, ingredient := range all {
+		ids[i] = ingredient.ID
+	}
+	existing, err := nutrients.FindByIngredients(ctx, ids)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var pending []models.Ingredient
+	var names []string
+	for _, ingredient := range all {
+		if _, ok := existing[ingredient.ID]; ok && !*overwrite {
+			continue
+		}
+		pending = append(pending, ingredient)
+		names = append(names, ingredient.Name)
+	}
+
+	log.Printf("Mencari %d bahan di %s", len(names), *dir)
+	foods, err := nutrition.ImportFDC(*dir, names)
+	if err != nil {
+		log.Fatal("Gagal membaca dump FoodData Central: ", err)
+	}
+
+	var imported, missing int
+	for _, ingredient := range pending {
+		food, ok := foods[ingredient.Name]
+		if !ok {
+			log.Printf("Tidak ditemukan: %s", ingredient.Name)
+			missing++
+			continue
+		}
+		log.Printf("%s -> %s (fdc_id %d, %s)", ingredient.Name, food.Description, food.FdcId, food.DataType)
+		if *dryRun {
+			imported++
+			continue
+		}
+
+		nutrient := models.IngredientNutrient{
+			IngredientId: ingredient.ID,
+			FdcId:        food.FdcId,
+			Description:  food.Description,
+		}
+		food.Facts.Apply(&nutrient)
+		if err := nutrients.Save(ctx, &nutrient); err != nil {
+			log.Fatalf("Gagal menyimpan data gizi %s: %v", ingredient.Name, err)
+		}
+		imported++
+	}
+	log.Printf("Selesai: %d cocok, %d tidak ditemukan", imported, missing)
+}
@@ -0,0 +1,57 @@
+// Command user mengatur role user langsung di database, misalnya untuk
+// menjadikan user pertama sebagai admin.
+//
+//	go run ./cmd/user -login alice -role admin
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"go-rest-modul/auth"
+	"go-rest-modul/config"
+	"go-rest-modul/database"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "path ke file konfigurasi YAML/TOML (opsional)")
+	login := flag.String("login", "", "username atau email user")
+	role := flag.String("role", "", "role baru: "+strings.Join(models.Roles, ", "))
+	flag.Parse()
+
+	if *login == "" {
+		log.Fatal("Parameter -login wajib diisi")
+	}
+	if !auth.ValidRole(*role) {
+		log.Fatalf("Role %q tidak dikenal", *role)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Konfigurasi tidak valid: ", err)
+	}
+	db, err := database.Connect(cfg.Database, cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	users := repository.NewUserRepository(db)
+
+	user, err := users.FindByLogin(ctx, *login)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Fatalf("User %s tidak ditemukan", *login)
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := users.UpdateRole(ctx, user.ID, *role); err != nil {
+		log.Fatal("Gagal mengubah role: ", err)
+	}
+	log.Printf("Role %s sekarang %s; berlaku setelah token di-refresh", user.Username, *role)
+}
@@ -0,0 +1,369 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix adalah awalan semua environment variable konfigurasi.
+const EnvPrefix = "RECIPEBOOK_"
+
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Storage  StorageConfig  `yaml:"storage" toml:"storage"`
+	LogLevel string         `yaml:"log_level" toml:"log_level"`
+}
+
+type ServerConfig struct {
+	Addr            string        `yaml:"addr" toml:"addr"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// Driver database yang didukung.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" toml:"driver"`
+	Path            string        `yaml:"path" toml:"path"` // file SQLite, hanya untuk driver sqlite
+	Host            string        `yaml:"host" toml:"host"`
+	Port            int           `yaml:"port" toml:"port"`
+	User            string        `yaml:"user" toml:"user"`
+	Password        string        `yaml:"password" toml:"password"`
+	Name            string        `yaml:"name" toml:"name"`
+	SSLMode         string        `yaml:"sslmode" toml:"sslmode"`
+	TimeZone        string        `yaml:"timezone" toml:"timezone"`
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+}
+
+type AuthConfig struct {
+	// Secret adalah kunci HMAC untuk menandatangani JWT, minimal 32 byte.
+	// Jika kosong server membuat kunci acak sehingga semua token batal
+	// setiap kali server dijalankan ulang.
+	Secret     string        `yaml:"secret" toml:"secret"`
+	Issuer     string        `yaml:"issuer" toml:"issuer"`
+	AccessTTL  time.Duration `yaml:"access_ttl" toml:"access_ttl"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" toml:"refresh_ttl"`
+	// PublicReads mengizinkan request GET tanpa login. Request yang mengubah
+	// data selalu wajib login.
+	PublicReads bool `yaml:"public_reads" toml:"public_reads"`
+	// DefaultRole adalah role untuk user yang baru mendaftar. Default-nya
+	// viewer agar pendaftaran terbuka tidak langsung bisa mengubah data global.
+	DefaultRole string `yaml:"default_role" toml:"default_role"`
+}
+
+// Driver storage untuk file unggahan.
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+type StorageConfig struct {
+	Driver string `yaml:"driver" toml:"driver"`
+	// PublicURL adalah awalan URL file yang disimpan. Untuk driver local
+	// nilai berawalan "/" dilayani langsung oleh server ini. Untuk driver s3
+	// boleh kosong (URL dibentuk dari endpoint) atau diisi URL CDN.
+	PublicURL string   `yaml:"public_url" toml:"public_url"`
+	LocalDir  string   `yaml:"local_dir" toml:"local_dir"` // hanya untuk driver local
+	S3        S3Config `yaml:"s3" toml:"s3"`
+	// MaxUploadSize adalah ukuran file gambar maksimum dalam byte
+	MaxUploadSize int `yaml:"max_upload_size" toml:"max_upload_size"`
+	// MaxPixels membatasi lebar x tinggi gambar agar decode tidak
+	// menghabiskan memori
+	MaxPixels int `yaml:"max_pixels" toml:"max_pixels"`
+}
+
+type S3Config struct {
+	// Endpoint misalnya https://s3.ap-southeast-1.amazonaws.com atau
+	// http://localhost:9000 untuk MinIO
+	Endpoint  string `yaml:"endpoint" toml:"endpoint"`
+	Region    string `yaml:"region" toml:"region"`
+	Bucket    string `yaml:"bucket" toml:"bucket"`
+	AccessKey string `yaml:"access_key" toml:"access_key"`
+	SecretKey string `yaml:"secret_key" toml:"secret_key"`
+	// PathStyle memakai URL endpoint/bucket/key, dibutuhkan MinIO dan
+	// kebanyakan stand-in S3 lokal
+	PathStyle bool `yaml:"path_style" toml:"path_style"`
+}
+
+// MinSecretLength adalah panjang minimal auth.secret dalam byte.
+const MinSecretLength = 32
+
+// DSN menyusun connection string Postgres dari konfigurasi. Setiap nilai
+// diberi kutip agar password kosong atau berisi spasi tetap terbaca benar.
+func (c DatabaseConfig) DSN() string {
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return fmt.Sprintf("host='%s' port=%d user='%s' password='%s' dbname='%s' sslmode='%s' TimeZone='%s'",
+		quote.Replace(c.Host), c.Port, quote.Replace(c.User), quote.Replace(c.Password),
+		quote.Replace(c.Name), quote.Replace(c.SSLMode), quote.Replace(c.TimeZone))
+}
+
+// Default mengembalikan konfigurasi bawaan untuk development lokal.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Path:            "recipebook.db",
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "recipe_db",
+			SSLMode:         "disable",
+			TimeZone:        "Asia/Jakarta",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			Issuer:      "recipebook",
+			AccessTTL:   15 * time.Minute,
+			RefreshTTL:  30 * 24 * time.Hour,
+			PublicReads: true,
+			DefaultRole: models.RoleViewer,
+		},
+		Storage: StorageConfig{
+			Driver:        StorageLocal,
+			PublicURL:     "/media",
+			LocalDir:      "uploads",
+			S3:            S3Config{Region: "us-east-1"},
+			MaxUploadSize: 10 << 20,
+			MaxPixels:     40_000_000,
+		},
+		LogLevel: "info",
+	}
+}
+
+// Load membaca konfigurasi dengan urutan prioritas: default, file (jika path
+// tidak kosong), lalu environment variable. Hasil akhir selalu divalidasi.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	strs := map[string]*string{
+		"ADDR":               &cfg.Server.Addr,
+		"LOG_LEVEL":          &cfg.LogLevel,
+		"DB_DRIVER":          &cfg.Database.Driver,
+		"DB_PATH":            &cfg.Database.Path,
+		"DB_HOST":            &cfg.Database.Host,
+		"DB_USER":            &cfg.Database.User,
+		"DB_PASSWORD":        &cfg.Database.Password,
+		"DB_NAME":            &cfg.Database.Name,
+		"DB_SSLMODE":         &cfg.Database.SSLMode,
+		"DB_TIMEZONE":        &cfg.Database.TimeZone,
+		"AUTH_SECRET":        &cfg.Auth.Secret,
+		"AUTH_ISSUER":        &cfg.Auth.Issuer,
+		"AUTH_DEFAULT_ROLE":  &cfg.Auth.DefaultRole,
+		"STORAGE_DRIVER":     &cfg.Storage.Driver,
+		"STORAGE_PUBLIC_URL": &cfg.Storage.PublicURL,
+		"STORAGE_LOCAL_DIR":  &cfg.Storage.LocalDir,
+		"S3_ENDPOINT":        &cfg.Storage.S3.Endpoint,
+		"S3_REGION":          &cfg.Storage.S3.Region,
+		"S3_BUCKET":          &cfg.Storage.S3.Bucket,
+		"S3_ACCESS_KEY":      &cfg.Storage.S3.AccessKey,
+		"S3_SECRET_KEY":      &cfg.Storage.S3.SecretKey,
+	}
+	for key, dst := range strs {
+		if v, ok := os.LookupEnv(EnvPrefix + key); ok {
+			*dst = v
+		}
+	}
+
+	ints := map[string]*int{
+		"DB_PORT":                 &cfg.Database.Port,
+		"DB_MAX_OPEN_CONNS":       &cfg.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":       &cfg.Database.MaxIdleConns,
+		"STORAGE_MAX_UPLOAD_SIZE": &cfg.Storage.MaxUploadSize,
+		"STORAGE_MAX_PIXELS":      &cfg.Storage.MaxPixels,
+	}
+	for key, dst := range ints {
+		if v, ok := os.LookupEnv(EnvPrefix + key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", EnvPrefix, key, err)
+			}
+			*dst = n
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"READ_TIMEOUT":         &cfg.Server.ReadTimeout,
+		"WRITE_TIMEOUT":        &cfg.Server.WriteTimeout,
+		"IDLE_TIMEOUT":         &cfg.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":     &cfg.Server.ShutdownTimeout,
+		"DB_CONN_MAX_LIFETIME": &cfg.Database.ConnMaxLifetime,
+		"AUTH_ACCESS_TTL":      &cfg.Auth.AccessTTL,
+		"AUTH_REFRESH_TTL":     &cfg.Auth.RefreshTTL,
+	}
+	for key, dst := range durations {
+		if v, ok := os.LookupEnv(EnvPrefix + key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", EnvPrefix, key, err)
+			}
+			*dst = d
+		}
+	}
+
+	bools := map[string]*bool{
+		"AUTH_PUBLIC_READS": &cfg.Auth.PublicReads,
+		"S3_PATH_STYLE":     &cfg.Storage.S3.PathStyle,
+	}
+	for key, dst := range bools {
+		if v, ok := os.LookupEnv(EnvPrefix + key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", EnvPrefix, key, err)
+			}
+			*dst = b
+		}
+	}
+	return nil
+}
+
+// Validate memeriksa nilai konfigurasi dan menggabungkan semua kesalahan.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr cannot be empty"))
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.ShutdownTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts cannot be negative"))
+	}
+
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.Host == "" {
+			errs = append(errs, errors.New("database.host cannot be empty"))
+		}
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			errs = append(errs, fmt.Errorf("database.port %d is out of range", c.Database.Port))
+		}
+		if c.Database.Name == "" {
+			errs = append(errs, errors.New("database.name cannot be empty"))
+		}
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			errs = append(errs, errors.New("database.path cannot be empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("database.driver %q must be one of %s, %s", c.Database.Driver, DriverPostgres, DriverSQLite))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database pool sizes cannot be negative"))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns cannot exceed max_open_conns"))
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("database.conn_max_lifetime cannot be negative"))
+	}
+
+	if c.Auth.Secret != "" && len(c.Auth.Secret) < MinSecretLength {
+		errs = append(errs, fmt.Errorf("auth.secret must be at least %d bytes", MinSecretLength))
+	}
+	if c.Auth.AccessTTL <= 0 || c.Auth.RefreshTTL <= 0 {
+		errs = append(errs, errors.New("auth token ttls must be positive"))
+	}
+	if c.Auth.RefreshTTL < c.Auth.AccessTTL {
+		errs = append(errs, errors.New("auth.refresh_ttl cannot be shorter than access_ttl"))
+	}
+	if !slices.Contains(models.Roles, c.Auth.DefaultRole) {
+		errs = append(errs, fmt.Errorf("auth.default_role %q must be one of %s", c.Auth.DefaultRole, strings.Join(models.Roles, ", ")))
+	}
+
+	switch c.Storage.Driver {
+	case StorageLocal:
+		if c.Storage.LocalDir == "" {
+			errs = append(errs, errors.New("storage.local_dir cannot be empty"))
+		}
+		if c.Storage.PublicURL == "" {
+			errs = append(errs, errors.New("storage.public_url cannot be empty"))
+		}
+	case StorageS3:
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" || c.Storage.S3.Region == "" {
+			errs = append(errs, errors.New("storage.s3 endpoint, bucket and region cannot be empty"))
+		}
+		if c.Storage.S3.AccessKey == "" || c.Storage.S3.SecretKey == "" {
+			errs = append(errs, errors.New("storage.s3 access_key and secret_key cannot be empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.driver %q must be one of %s, %s", c.Storage.Driver, StorageLocal, StorageS3))
+	}
+	if c.Storage.MaxUploadSize <= 0 {
+		errs = append(errs, errors.New("storage.max_upload_size must be positive"))
+	}
+	if c.Storage.MaxPixels <= 0 {
+		errs = append(errs, errors.New("storage.max_pixels must be positive"))
+	}
+
+	switch strings.ToLower(c.LogLevel) {
+	case "silent", "error", "warn", "info":
+	default:
+		errs = append(errs, fmt.Errorf("log_level %q must be one of silent, error, warn, info", c.LogLevel))
+	}
+
+	return errors.Join(errs...)
+}
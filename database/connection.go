@@ -1,26 +1,93 @@
 package database
 
 import (
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"go-rest-modul/config"
 	"go-rest-modul/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 	"log"
+	"strings"
 )
 
-var DB *gorm.DB
+// Connect membuka koneksi database sesuai konfigurasi, mengatur pool koneksi,
+// lalu menjalankan migrasi.
+func Connect(cfg config.DatabaseConfig, logLevel string) (*gorm.DB, error) {
+	dialector, err := dialectorFor(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-func init() {
-	dsn := "host=localhost port=5432 user=postgres password=123 dbname=recipe_db sslmode=disable TimeZone=Asia/Jakarta"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(gormLogLevel(logLevel)),
+	})
 	if err != nil {
 		log.Println("Tidak dapat terhubung ke database")
-		panic(err.Error())
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.Driver == config.DriverSQLite {
+		// SQLite hanya mengizinkan satu penulis, dan database :memory: berbeda
+		// untuk tiap koneksi, jadi cukup satu koneksi saja
+		sqlDB.SetMaxOpenConns(1)
 	}
-	DB = db
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
 	log.Println("Berhasil terhubung ke database")
 
-	err = db.AutoMigrate(&models.Category{}, &models.Recipe{}, &models.Ingredient{}, &models.RecipeIngredient{})
+	err = db.AutoMigrate(&models.Category{}, &models.Tag{}, &models.Recipe{}, &models.Ingredient{}, &models.RecipeIngredient{},
+		&models.ShoppingList{}, &models.ShoppingListRecipe{}, &models.ShoppingListItem{},
+		&models.MealPlan{}, &models.MealPlanEntry{}, &models.IngredientNutrient{},
+		&models.User{}, &models.Session{}, &models.Review{},
+		&models.Favorite{}, &models.Collection{}, &models.CollectionEntry{}, &models.RecipeImage{},
+		&models.RecipeRevision{}, &models.RecipeRevisionIngredient{})
 	if err != nil {
 		log.Println("Gagal melakukan migrasi")
 	}
+
+	if err := migrateSearch(db); err != nil {
+		log.Println("Gagal menyiapkan full-text search")
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqlitePragmas menyamakan perilaku SQLite dengan Postgres: foreign key
+// ditegakkan dan LIKE bersifat case-sensitive.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=case_sensitive_like(1)&_pragma=busy_timeout(5000)"
+
+func dialectorFor(cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		return postgres.Open(cfg.DSN()), nil
+	case config.DriverSQLite:
+		separator := "?"
+		if strings.Contains(cfg.Path, "?") {
+			separator = "&"
+		}
+		return sqlite.Open(cfg.Path + separator + sqlitePragmas), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+func gormLogLevel(level string) logger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
 }
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// searchMigrations menyiapkan full-text search Postgres: kolom tsvector pada
+// recipes, index GIN, dan trigger yang memperbarui kolom tersebut setiap kali
+// recipe, baris bahan, atau nama bahan berubah. Bobot: title (A), descriptions
+// (B), instructions (C), nama bahan (D). Konfigurasi 'simple' dipakai karena
+// resep bercampur bahasa Indonesia dan Inggris, jadi tanpa stemming.
+var searchMigrations = []string{
+	`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`CREATE INDEX IF NOT EXISTS idx_recipes_search_vector ON recipes USING GIN (search_vector)`,
+	`CREATE OR REPLACE FUNCTION recipes_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector :=
+		setweight(to_tsvector('simple', coalesce(NEW.title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(NEW.descriptions, '')), 'B') ||
+		setweight(to_tsvector('simple', coalesce(NEW.instructions, '')), 'C') ||
+		setweight(to_tsvector('simple', coalesce((
+			SELECT string_agg(i.name, ' ')
+			FROM recipe_ingredients ri
+			JOIN ingredients i ON i.id = ri.ingredient_id
+			WHERE ri.recipe_id = NEW.id AND ri.deleted_at IS NULL
+		), '')), 'D');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS recipes_search_vector_trigger ON recipes`,
+	`CREATE TRIGGER recipes_search_vector_trigger
+	BEFORE INSERT OR UPDATE ON recipes
+	FOR EACH ROW EXECUTE FUNCTION recipes_search_vector_update()`,
+	// Perubahan baris bahan memicu hitung ulang lewat trigger recipes di atas
+	`CREATE OR REPLACE FUNCTION recipe_ingredients_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		UPDATE recipes SET search_vector = NULL WHERE id = OLD.recipe_id;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		UPDATE recipes SET search_vector = NULL WHERE id = NEW.recipe_id;
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS recipe_ingredients_search_vector_trigger ON recipe_ingredients`,
+	`CREATE TRIGGER recipe_ingredients_search_vector_trigger
+	AFTER INSERT OR UPDATE OR DELETE ON recipe_ingredients
+	FOR EACH ROW EXECUTE FUNCTION recipe_ingredients_search_vector_refresh()`,
+	`CREATE OR REPLACE FUNCTION ingredients_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+	UPDATE recipes SET search_vector = NULL
+	WHERE id IN (SELECT recipe_id FROM recipe_ingredients WHERE ingredient_id = NEW.id);
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS ingredients_search_vector_trigger ON ingredients`,
+	`CREATE TRIGGER ingredients_search_vector_trigger
+	AFTER UPDATE OF name ON ingredients
+	FOR EACH ROW EXECUTE FUNCTION ingredients_search_vector_refresh()`,
+	// Isi kolom untuk data yang sudah ada sebelum migrasi ini
+	`UPDATE recipes SET search_vector = NULL WHERE search_vector IS NULL`,
+}
+
+// migrateSearch hanya berlaku untuk Postgres. Driver lain memakai pencarian LIKE.
+func migrateSearch(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range searchMigrations {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
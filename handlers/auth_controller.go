@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type AuthHandler struct {
+	users       repository.UserRepository
+	tokens      *auth.Tokens
+	defaultRole string
+}
+
+func NewAuthHandler(users repository.UserRepository, tokens *auth.Tokens, defaultRole string) *AuthHandler {
+	return &AuthHandler{users: users, tokens: tokens, defaultRole: defaultRole}
+}
+
+// RegisterInput adalah payload registrasi user baru.
+type RegisterInput struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginInput berisi username atau email beserta password.
+type LoginInput struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// RefreshInput dipakai oleh endpoint refresh dan logout.
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AuthResult adalah data user beserta token yang baru diterbitkan.
+type AuthResult struct {
+	User models.User `json:"user"`
+	auth.Pair
+}
+
+// issueTokens membuat session baru dan menerbitkan token untuk user.
+func (h *AuthHandler) issueTokens(r *http.Request, user models.User) (auth.Pair, error) {
+	now := time.Now()
+	session := models.Session{
+		UserId:    user.ID,
+		TokenId:   auth.NewSessionId(),
+		ExpiresAt: h.tokens.RefreshExpiry(now),
+	}
+	if err := h.users.CreateSession(r.Context(), &session); err != nil {
+		return auth.Pair{}, err
+	}
+	return h.tokens.Issue(user, session.TokenId, now)
+}
+
+func writeTokenError(w http.ResponseWriter, err error) {
+	response := Response{
+		Status:  "error",
+		Message: "Error occured while issuing token :" + err.Error(),
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var input RegisterInput
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	input.Username = strings.TrimSpace(input.Username)
+	input.Email = strings.TrimSpace(input.Email)
+	var message string
+	if n := utf8.RuneCountInString(input.Username); n < 3 || n > 32 || strings.ContainsAny(input.Username, " @") {
+		message = "Username must be 3 to 32 characters without spaces or @"
+	} else if address, err := mail.ParseAddress(input.Email); err != nil || address.Address != input.Email {
+		message = "Email is not valid"
+	} else if err := auth.ValidatePassword(input.Password); err != nil {
+		message = "Password " + strings.TrimPrefix(err.Error(), auth.ErrInvalidPassword.Error()+": ")
+	}
+	if message != "" {
+		response := Response{
+			Status:  "error",
+			Message: message,
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while hashing password :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	user := models.User{Username: input.Username, Email: input.Email, Role: h.defaultRole, PasswordHash: hash}
+	if err := h.users.Create(r.Context(), &user); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			response := Response{
+				Status:  "error",
+				Message: "Username or email already registered",
+			}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error while creating data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pair, err := h.issueTokens(r, user)
+	if err != nil {
+		writeTokenError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "User Registered Successfully",
+		Data:    AuthResult{User: user, Pair: pair},
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input LoginInput
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	user, err := h.users.FindByLogin(r.Context(), strings.TrimSpace(input.Login))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while retrieving data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Pesan yang sama untuk user tidak ada dan password salah
+	valid := false
+	if err == nil {
+		valid = auth.CheckPassword(user.PasswordHash, input.Password)
+	} else {
+		auth.CheckDummyPassword(input.Password)
+	}
+	if !valid {
+		response := Response{
+			Status:  "error",
+			Message: "Invalid login or password",
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pair, err := h.issueTokens(r, user)
+	if err != nil {
+		writeTokenError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Logged In Successfully",
+		Data:    AuthResult{User: user, Pair: pair},
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Refresh menukar refresh token dengan pasangan token baru. Refresh token
+// lama langsung dicabut; jika token yang sudah dicabut dipakai lagi, semua
+// session user dicabut karena token kemungkinan telah dicuri.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input RefreshInput
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	claims, err := h.tokens.Parse(input.RefreshToken, auth.TokenRefresh)
+	if err != nil {
+		writeUnauthorized(w, "Invalid refresh token")
+		return
+	}
+	userId, _ := claims.UserId()
+
+	user, err := h.users.FindByID(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUnauthorized(w, "Invalid refresh token")
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while retrieving data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	now := time.Now()
+	next := models.Session{
+		UserId:    user.ID,
+		TokenId:   auth.NewSessionId(),
+		ExpiresAt: h.tokens.RefreshExpiry(now),
+	}
+	if err := h.users.RotateSession(r.Context(), claims.ID, &next); err != nil {
+		if errors.Is(err, repository.ErrSessionRevoked) {
+			h.users.RevokeUserSessions(r.Context(), user.ID)
+			writeUnauthorized(w, "Refresh token has been revoked")
+			return
+		}
+		writeTokenError(w, err)
+		return
+	}
+
+	pair, err := h.tokens.Issue(user, next.TokenId, now)
+	if err != nil {
+		writeTokenError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Token Refreshed Successfully",
+		Data:    AuthResult{User: user, Pair: pair},
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Logout mencabut refresh token. Access token yang sudah terbit tetap
+// berlaku sampai kedaluwarsa, karena itu masa berlakunya dibuat pendek.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var input RefreshInput
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	claims, err := h.tokens.Parse(input.RefreshToken, auth.TokenRefresh)
+	if err != nil {
+		writeUnauthorized(w, "Invalid refresh token")
+		return
+	}
+
+	if err := h.users.RevokeSession(r.Context(), claims.ID); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while revoking token :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Logged Out Successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Me mengembalikan data user yang sedang login.
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	principal, _ := auth.FromContext(r.Context())
+	user, err := h.users.FindByID(r.Context(), principal.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUnauthorized(w, "User no longer exists")
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while retrieving data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "User Retrieved Successfully",
+		Data:    user,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
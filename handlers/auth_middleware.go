@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-rest-modul/auth"
+	"net/http"
+	"strings"
+)
+
+// Authenticator adalah middleware mux untuk membaca access token dan
+// membatasi route yang wajib login.
+type Authenticator struct {
+	tokens      *auth.Tokens
+	publicReads bool
+}
+
+// NewAuthenticator membuat Authenticator. Jika publicReads bernilai false,
+// request baca pada route yang dilindungi Protect juga wajib login.
+func NewAuthenticator(tokens *auth.Tokens, publicReads bool) *Authenticator {
+	return &Authenticator{tokens: tokens, publicReads: publicReads}
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="recipebook"`)
+	response := Response{
+		Status:  "error",
+		Message: message,
+	}
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeForbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	response := Response{
+		Status:  "error",
+		Message: message,
+	}
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Authenticate menyimpan principal di context jika request membawa header
+// Authorization: Bearer. Request tanpa token tetap diteruskan, sedangkan
+// token yang tidak valid langsung ditolak.
+func (a *Authenticator) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			writeUnauthorized(w, "Authorization header must use the Bearer scheme")
+			return
+		}
+		claims, err := a.tokens.Parse(strings.TrimSpace(token), auth.TokenAccess)
+		if err != nil {
+			writeUnauthorized(w, "Invalid or expired access token")
+			return
+		}
+		userId, _ := claims.UserId()
+
+		ctx := auth.NewContext(r.Context(), auth.Principal{UserId: userId, Username: claims.Username, Role: claims.Role})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Protect mewajibkan login untuk request yang mengubah data, dan untuk
+// request baca jika public reads dimatikan.
+func (a *Authenticator) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if a.publicReads {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		a.RequireUser(next).ServeHTTP(w, r)
+	})
+}
+
+// RequireUser mewajibkan login apa pun method-nya.
+func (a *Authenticator) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.FromContext(r.Context()); !ok {
+			writeUnauthorized(w, "Authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require mewajibkan login dan permission tertentu. Dipakai saat route
+// didaftarkan sehingga handler tidak perlu memeriksa role sendiri.
+func (a *Authenticator) Require(permission auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return a.RequireUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := auth.FromContext(r.Context())
+			if !principal.Can(permission) {
+				writeForbidden(w, "Permission denied: "+string(permission)+" is required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
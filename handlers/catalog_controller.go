@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-rest-modul/auth"
+	"go-rest-modul/catalog"
+	"go-rest-modul/repository"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxCatalogSize membatasi ukuran file katalog yang diimpor.
+const maxCatalogSize = 50 << 20
+
+type CatalogHandler struct {
+	catalog repository.CatalogRepository
+}
+
+func NewCatalogHandler(catalog repository.CatalogRepository) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog}
+}
+
+// catalogFormat membaca format dari parameter format, atau dari media type
+// pada header (Content-Type untuk impor, Accept untuk ekspor). Jika keduanya
+// kosong format CSV yang dipakai.
+func catalogFormat(r *http.Request, header string) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return catalog.ParseFormat(format)
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(header))
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/json":
+		return catalog.FormatNDJSON, nil
+	default:
+		return catalog.FormatCSV, nil
+	}
+}
+
+func writeInvalidCatalogFormat(w http.ResponseWriter) {
+	response := Response{
+		Status:  "error",
+		Message: "format must be csv or ndjson",
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ImportCatalogHandler mengimpor category, ingredient, recipe dan baris bahan
+// dari CSV atau NDJSON. Data yang sudah ada dicocokkan lewat nama/judul lalu
+// diperbarui. Dengan dry_run=true atau jika ada baris yang error, tidak ada
+// perubahan yang disimpan dan laporan per baris tetap dikembalikan.
+func (h *CatalogHandler) ImportCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	format, err := catalogFormat(r, "Content-Type")
+	if err != nil {
+		writeInvalidCatalogFormat(w)
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "dry_run must be true or false",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		dryRun = parsed
+	}
+
+	records, readErrors, err := catalog.Read(http.MaxBytesReader(w, r.Body, maxCatalogSize), format)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while reading data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Baris yang gagal dibaca tetap divalidasi sisanya, tapi tidak disimpan
+	principal, _ := auth.FromContext(r.Context())
+	report, err := h.catalog.Import(r.Context(), records, principal.UserId, dryRun || len(readErrors) > 0)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while importing data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	report.DryRun = dryRun
+	report.AddReadErrors(readErrors)
+
+	if len(report.Errors) > 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Catalog Import Failed, No Changes Saved",
+			Data:    report,
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	message := "Catalog Imported Successfully"
+	if dryRun {
+		message = "Catalog Import Dry Run Successful"
+	}
+	response := Response{
+		Status:  "success",
+		Message: message,
+		Data:    report,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ExportCatalogHandler men-stream seluruh katalog dalam format yang sama
+// dengan impor, sehingga hasilnya bisa langsung diimpor kembali.
+func (h *CatalogHandler) ExportCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	format, err := catalogFormat(r, "Accept")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeInvalidCatalogFormat(w)
+		return
+	}
+
+	if format == catalog.FormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="catalog.csv"`)
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="catalog.ndjson"`)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	// Status sudah terkirim, jadi error di tengah stream hanya menghentikan
+	// penulisan; client akan menerima file yang terpotong
+	writer, err := catalog.NewWriter(w, format)
+	if err != nil {
+		return
+	}
+	if err := h.catalog.Export(r.Context(), writer.Write); err != nil {
+		return
+	}
+	writer.Flush()
+}
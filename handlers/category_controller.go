@@ -1,22 +1,84 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"go-rest-modul/database"
 	"go-rest-modul/models"
+	"go-rest-modul/repository"
 	"net/http"
-
-	"github.com/gorilla/mux"
-	"gorm.io/gorm"
+	"strings"
 )
 
-func GetAllCategory(w http.ResponseWriter, r *http.Request) {
-	var category []models.Category
+type CategoryHandler struct {
+	categories repository.CategoryRepository
+}
+
+func NewCategoryHandler(categories repository.CategoryRepository) *CategoryHandler {
+	return &CategoryHandler{categories: categories}
+}
+
+// CategoryDetail adalah category beserta jalurnya dari category paling atas,
+// misalnya Indonesian > Javanese > Soto.
+type CategoryDetail struct {
+	models.Category
+	Breadcrumbs []repository.CategoryCrumb `json:"breadcrumbs"`
+	Path        string                     `json:"path"`
+}
+
+// withBreadcrumbs melengkapi setiap category dengan breadcrumb-nya.
+func (h *CategoryHandler) withBreadcrumbs(ctx context.Context, categories ...models.Category) ([]CategoryDetail, error) {
+	ids := make([]uint, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	breadcrumbs, err := h.categories.Breadcrumbs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]CategoryDetail, len(categories))
+	for i, category := range categories {
+		crumbs := breadcrumbs[category.ID]
+		names := make([]string, len(crumbs))
+		for j, crumb := range crumbs {
+			names[j] = crumb.Name
+		}
+		details[i] = CategoryDetail{Category: category, Breadcrumbs: crumbs, Path: strings.Join(names, " > ")}
+	}
+	return details, nil
+}
 
+// writeCategoryParentError menulis response untuk parent_id yang tidak valid.
+// ok bernilai false jika err bukan error parent.
+func writeCategoryParentError(w http.ResponseWriter, err error) (ok bool) {
+	if !errors.Is(err, repository.ErrCategoryParentNotFound) && !errors.Is(err, repository.ErrCategoryCycle) {
+		return false
+	}
+	response := Response{
+		Status:  "error",
+		Message: err.Error(),
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+	return true
+}
+
+func (h *CategoryHandler) GetAllCategory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	if err := database.DB.Preload("Recipes").Find(&category).Error; err != nil {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	category, total, err := h.categories.List(r.Context(), opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "error occured while retrieving data: " + err.Error(),
@@ -26,7 +88,7 @@ func GetAllCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(category) == 0 {
+	if total == 0 {
 		response := Response{
 			Status:  "not found",
 			Message: "Category not found",
@@ -36,23 +98,44 @@ func GetAllCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var lastID uint
+	if len(category) > 0 {
+		lastID = category[len(category)-1].ID
+	}
+
+	details, err := h.withBreadcrumbs(r.Context(), category...)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
 	response := Response{
-		Status:  "success",
-		Message: "Category retrieved successfully",
-		Data:    category,
+		Status:     "success",
+		Message:    "Category retrieved successfully",
+		Data:       details,
+		Pagination: newPagination(r, opts, total, len(category), lastID),
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func GetCategorybyId(w http.ResponseWriter, r *http.Request) {
-	var categoryId = mux.Vars(r)["id"]
-	var category models.Category
-
+func (h *CategoryHandler) GetCategorybyId(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	if err := database.DB.First(&category, categoryId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	categoryId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	category, err := h.categories.FindByID(r.Context(), categoryId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "not found",
 				Message: "Category not found",
@@ -70,16 +153,27 @@ func GetCategorybyId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	details, err := h.withBreadcrumbs(r.Context(), category)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
 	response := Response{
 		Status:  "success",
 		Message: "Category retrieved successfully",
-		Data:    category,
+		Data:    details[0],
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func CreateCategory(w http.ResponseWriter, r *http.Request) {
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	var category models.Category
 
 	w.Header().Set("Content-Type", "application/json")
@@ -104,8 +198,7 @@ func CreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var existing models.Category
-	if err := database.DB.Where("name = ?", category.Name).First(&existing).Error; err == nil {
+	if _, err := h.categories.FindByName(r.Context(), category.Name); err == nil {
 		response := Response{
 			Status:  "error",
 			Message: "Category already exists",
@@ -113,7 +206,7 @@ func CreateCategory(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusConflict)
 		json.NewEncoder(w).Encode(response)
 		return
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+	} else if !errors.Is(err, repository.ErrNotFound) {
 		response := Response{
 			Status:  "error",
 			Message: "Failed to check category: " + err.Error(),
@@ -123,7 +216,10 @@ func CreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := database.DB.Create(&category).Error; err != nil {
+	if err := h.categories.Create(r.Context(), &category); err != nil {
+		if writeCategoryParentError(w, err) {
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "Error while creating data: " + err.Error(),
@@ -133,24 +229,34 @@ func CreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Breadcrumb hanya pelengkap; jika gagal dibaca category tetap dikembalikan
+	detail := CategoryDetail{Category: category}
+	if details, err := h.withBreadcrumbs(r.Context(), category); err == nil {
+		detail = details[0]
+	}
+
 	response := Response{
 		Status:  "success",
 		Message: "Data created successfully",
-		Data:    category,
+		Data:    detail,
 	}
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
-func UpdateCategory(w http.ResponseWriter, r *http.Request) {
-	var categoryId = mux.Vars(r)["id"]
-	var category models.Category
-
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	categoryId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
 	// Cari data lama
-	if err := database.DB.First(&category, categoryId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	category, err := h.categories.FindByID(r.Context(), categoryId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "error",
 				Message: "Couldn't find category",
@@ -171,6 +277,8 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	// Decode ke struct baru
 	var input struct {
 		Name string `json:"name"`
+		// nil berarti parent tidak diubah, 0 berarti menjadi category paling atas
+		ParentId *uint `json:"parent_id"`
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -183,6 +291,10 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Nama boleh kosong jika hanya memindahkan category
+	if input.Name == "" && input.ParentId != nil {
+		input.Name = category.Name
+	}
 	if input.Name == "" {
 		response := Response{
 			Status:  "error",
@@ -195,8 +307,7 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 
 	// Cek duplikasi nama (jika nama berubah)
 	if input.Name != category.Name {
-		var existing models.Category
-		if err := database.DB.Where("name = ?", input.Name).First(&existing).Error; err == nil {
+		if _, err := h.categories.FindByName(r.Context(), input.Name); err == nil {
 			response := Response{
 				Status:  "error",
 				Message: "Category name already exists",
@@ -204,7 +315,7 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusConflict)
 			json.NewEncoder(w).Encode(response)
 			return
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		} else if !errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "error",
 				Message: "Failed to check category: " + err.Error(),
@@ -217,8 +328,17 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 
 	// Update field yang diizinkan
 	category.Name = input.Name
+	if input.ParentId != nil {
+		category.ParentId = input.ParentId
+		if *input.ParentId == 0 {
+			category.ParentId = nil
+		}
+	}
 
-	if err := database.DB.Save(&category).Error; err != nil {
+	if err := h.categories.Update(r.Context(), &category); err != nil {
+		if writeCategoryParentError(w, err) {
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "error when update: " + err.Error(),
@@ -228,22 +348,30 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	detail := CategoryDetail{Category: category}
+	if details, err := h.withBreadcrumbs(r.Context(), category); err == nil {
+		detail = details[0]
+	}
+
 	response := Response{
 		Status:  "success",
 		Message: "Category has been updated",
-		Data:    category,
+		Data:    detail,
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func DeleteCategory(w http.ResponseWriter, r *http.Request){
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request){
 	w.Header().Set("Content-Type", "application/json")
-	var categoryId = mux.Vars(r)["id"]
-	var category models.Category
+	categoryId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
 
-	if err := database.DB.First(&category, categoryId).Error; err != nil{
-		if errors.Is(err, gorm.ErrRecordNotFound){
+	if _, err := h.categories.FindByID(r.Context(), categoryId); err != nil{
+		if errors.Is(err, repository.ErrNotFound){
 			response := Response{
 				Status: "error",
 				Message: "Category Not Found",
@@ -261,7 +389,7 @@ func DeleteCategory(w http.ResponseWriter, r *http.Request){
 		return
 	}
 
-	if err := database.DB.Unscoped().Delete(&category, categoryId).Error; err != nil{
+	if err := h.categories.Delete(r.Context(), categoryId); err != nil{
 		response := Response{
 			Status: "error",
 			Message: "An error occured while deleting category" + err.Error(),
@@ -278,3 +406,37 @@ func DeleteCategory(w http.ResponseWriter, r *http.Request){
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetCategoryTree mengembalikan semua category sebagai tree.
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tree, err := h.categories.Tree(r.Context())
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if len(tree) == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "Category not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Category tree retrieved successfully",
+		Data:    tree,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
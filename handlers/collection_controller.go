@@ -0,0 +1,554 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// CollectionHandler berisi endpoint favorite dan collection milik user yang
+// sedang login. Collection milik user lain dijawab 404, kecuali lewat link share.
+type CollectionHandler struct {
+	favorites   repository.FavoriteRepository
+	collections repository.CollectionRepository
+}
+
+func NewCollectionHandler(favorites repository.FavoriteRepository, collections repository.CollectionRepository) *CollectionHandler {
+	return &CollectionHandler{favorites: favorites, collections: collections}
+}
+
+// CollectionShare adalah collection beserta link share-nya.
+type CollectionShare struct {
+	models.Collection
+	ShareURL string `json:"share_url"`
+}
+
+// sharedCollectionPath adalah path publik untuk membaca collection lewat token.
+func sharedCollectionPath(token string) string {
+	return "/api/shared/collection/" + token
+}
+
+// writeCollectionError menangani error repository yang umum pada endpoint
+// collection: data tidak ditemukan, recipe tidak valid dan recipe ganda.
+func writeCollectionError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response := Response{
+			Status:  "not found",
+			Message: notFound,
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+	case errors.Is(err, repository.ErrInvalidCollectionEntry):
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+	case errors.Is(err, repository.ErrCollectionEntryExists):
+		response := Response{
+			Status:  "error",
+			Message: "Recipe is already in this collection",
+		}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+	default:
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	response := Response{
+		Status:  "error",
+		Message: "Decode error: " + err.Error(),
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) GetFavorites(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	favorites, total, err := h.favorites.List(r.Context(), principal.UserId, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "success",
+			Message: "You Have No Favorites Yet",
+			Data:    favorites,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(favorites) > 0 {
+		lastID = favorites[len(favorites)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Favorites Retrieved Successfully",
+		Data:       favorites,
+		Pagination: newPagination(r, opts, total, len(favorites), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddFavorite menandai recipe sebagai favorite. Memanggilnya lagi untuk
+// recipe yang sama tidak membuat data baru.
+func (h *CollectionHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipeId, err := parseID(r, "recipe_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	favorite, created, err := h.favorites.Add(r.Context(), principal.UserId, recipeId)
+	if err != nil {
+		writeCollectionError(w, err, "Recipe Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe Is Already a Favorite",
+		Data:    favorite,
+	}
+	if created {
+		response.Message = "Recipe Added to Favorites"
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipeId, err := parseID(r, "recipe_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.favorites.Remove(r.Context(), principal.UserId, recipeId); err != nil {
+		writeCollectionError(w, err, "Favorite Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe Removed from Favorites",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) GetAllCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collections, total, err := h.collections.List(r.Context(), principal.UserId, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "success",
+			Message: "You Have No Collections Yet",
+			Data:    collections,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(collections) > 0 {
+		lastID = collections[len(collections)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Collections Retrieved Successfully",
+		Data:       collections,
+		Pagination: newPagination(r, opts, total, len(collections), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) GetCollectionbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.FindByID(r.Context(), principal.UserId, collectionId)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Retrieved Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetSharedCollection membaca collection lewat token share, tanpa login.
+func (h *CollectionHandler) GetSharedCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collection, err := h.collections.FindByShareToken(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Retrieved Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		response := Response{
+			Status:  "error",
+			Message: "Name cannot be empty",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection := models.Collection{
+		UserId:      principal.UserId,
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Entries:     []models.CollectionEntry{},
+	}
+	if err := h.collections.Create(r.Context(), &collection); err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Created Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	var update repository.CollectionUpdate
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			response := Response{
+				Status:  "error",
+				Message: "Name cannot be empty",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		update.Name = &name
+	}
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		update.Description = &description
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.Update(r.Context(), principal.UserId, collectionId, update)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Updated Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.collections.Delete(r.Context(), principal.UserId, collectionId); err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Deleted Successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddCollectionRecipe menambahkan recipe di akhir collection.
+func (h *CollectionHandler) AddCollectionRecipe(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RecipeId uint `json:"recipe_id"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.AddRecipe(r.Context(), principal.UserId, collectionId, input.RecipeId)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe Added to Collection",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CollectionHandler) RemoveCollectionRecipe(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+	recipeId, err := parseID(r, "recipe_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.RemoveRecipe(r.Context(), principal.UserId, collectionId, recipeId)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Entry Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe Removed from Collection",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ReorderCollection menyusun ulang urutan recipe. recipe_ids harus berisi
+// semua recipe di collection, masing-masing tepat sekali.
+func (h *CollectionHandler) ReorderCollection(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RecipeIds []uint `json:"recipe_ids"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.Reorder(r.Context(), principal.UserId, collectionId, input.RecipeIds)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Reordered Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ShareCollection mengaktifkan link share. Link yang sudah ada dipakai lagi.
+func (h *CollectionHandler) ShareCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.Share(r.Context(), principal.UserId, collectionId)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Shared Successfully",
+		Data:    CollectionShare{Collection: collection, ShareURL: sharedCollectionPath(*collection.ShareToken)},
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UnshareCollection mencabut link share sehingga link lama tidak berlaku.
+func (h *CollectionHandler) UnshareCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	collectionId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	collection, err := h.collections.Unshare(r.Context(), principal.UserId, collectionId)
+	if err != nil {
+		writeCollectionError(w, err, "Collection Not Found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Collection Link Revoked Successfully",
+		Data:    collection,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// fakeRecipeRepository menyimpan recipe di memori. Method yang tidak dipakai
+// test diteruskan ke interface embedded yang nil, sehingga panic jika terpanggil.
+type fakeRecipeRepository struct {
+	repository.RecipeRepository
+
+	mu      sync.Mutex
+	recipes map[uint]models.Recipe
+	nextID  uint
+	// updates mencatat setiap RecipeUpdate yang diterima Update
+	updates []repository.RecipeUpdate
+}
+
+func newFakeRecipeRepository(recipes ...models.Recipe) *fakeRecipeRepository {
+	f := &fakeRecipeRepository{recipes: make(map[uint]models.Recipe)}
+	for _, recipe := range recipes {
+		f.recipes[recipe.ID] = recipe
+		if recipe.ID > f.nextID {
+			f.nextID = recipe.ID
+		}
+	}
+	return f
+}
+
+func (f *fakeRecipeRepository) List(ctx context.Context, rating repository.RatingFilter, opts repository.ListOptions) ([]models.Recipe, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recipes := make([]models.Recipe, 0, len(f.recipes))
+	for _, recipe := range f.recipes {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
+	return recipes, int64(len(recipes)), nil
+}
+
+func (f *fakeRecipeRepository) FindByID(ctx context.Context, id uint) (models.Recipe, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recipe, ok := f.recipes[id]
+	if !ok {
+		return models.Recipe{}, repository.ErrNotFound
+	}
+	return recipe, nil
+}
+
+func (f *fakeRecipeRepository) Create(ctx context.Context, recipe *models.Recipe, lines []repository.IngredientLine, tags []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, line := range lines {
+		if line.IngredientId == 0 && strings.TrimSpace(line.Name) == "" {
+			return repository.ErrInvalidIngredientLine
+		}
+	}
+	f.nextID++
+	recipe.ID = f.nextID
+	for i, line := range lines {
+		recipe.RecipeIngredients = append(recipe.RecipeIngredients, models.RecipeIngredient{
+			RecipeId:     recipe.ID,
+			IngredientId: uint(i + 1),
+			Ingredient:   models.Ingredient{Name: line.Name},
+			Amount:       line.Amount,
+			Unit:         line.Unit,
+		})
+	}
+	for _, name := range tags {
+		recipe.Tags = append(recipe.Tags, models.Tag{Name: name})
+	}
+	f.recipes[recipe.ID] = *recipe
+	return nil
+}
+
+func (f *fakeRecipeRepository) Update(ctx context.Context, id uint, update repository.RecipeUpdate) (models.Recipe, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recipe, ok := f.recipes[id]
+	if !ok {
+		return models.Recipe{}, repository.ErrNotFound
+	}
+	f.updates = append(f.updates, update)
+	if update.Title != nil {
+		recipe.Title = *update.Title
+	}
+	if update.Descriptions != nil {
+		recipe.Descriptions = *update.Descriptions
+	}
+	if update.Instructions != nil {
+		recipe.Instructions = *update.Instructions
+	}
+	if update.PrepTime != nil {
+		recipe.PrepTime = *update.PrepTime
+	}
+	if update.CookTime != nil {
+		recipe.CookTime = *update.CookTime
+	}
+	if update.Servings != nil {
+		recipe.Servings = *update.Servings
+	}
+	if update.ImageURL != nil {
+		recipe.ImageURL = *update.ImageURL
+	}
+	if update.CategoryId != nil {
+		recipe.CategoryId = *update.CategoryId
+	}
+	f.recipes[id] = recipe
+	return recipe, nil
+}
+
+func (f *fakeRecipeRepository) Delete(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.recipes[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.recipes, id)
+	return nil
+}
+
+// fakeCategoryRepository hanya mendukung FindByID.
+type fakeCategoryRepository struct {
+	repository.CategoryRepository
+	categories map[uint]models.Category
+}
+
+func newFakeCategoryRepository(categories ...models.Category) *fakeCategoryRepository {
+	f := &fakeCategoryRepository{categories: make(map[uint]models.Category)}
+	for _, category := range categories {
+		f.categories[category.ID] = category
+	}
+	return f
+}
+
+func (f *fakeCategoryRepository) FindByID(ctx context.Context, id uint) (models.Category, error) {
+	category, ok := f.categories[id]
+	if !ok {
+		return models.Category{}, repository.ErrNotFound
+	}
+	return category, nil
+}
+
+// fakeNutrientRepository tidak memiliki data gizi untuk bahan apa pun.
+type fakeNutrientRepository struct {
+	repository.NutrientRepository
+}
+
+func (fakeNutrientRepository) FindByIngredients(ctx context.Context, ingredientIds []uint) (map[uint]models.IngredientNutrient, error) {
+	return map[uint]models.IngredientNutrient{}, nil
+}
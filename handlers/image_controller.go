@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-rest-modul/images"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"go-rest-modul/storage"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+)
+
+// multipartOverhead adalah ruang tambahan untuk header dan boundary
+// multipart di atas batas ukuran file.
+const multipartOverhead = 64 << 10
+
+type ImageHandler struct {
+	recipes   repository.RecipeRepository
+	images    repository.RecipeImageRepository
+	store     storage.Storage
+	maxSize   int
+	maxPixels int
+}
+
+// NewImageHandler membuat handler unggah gambar. maxSize adalah ukuran file
+// maksimum dalam byte dan maxPixels batas lebar x tinggi gambar.
+func NewImageHandler(recipes repository.RecipeRepository, recipeImages repository.RecipeImageRepository, store storage.Storage, maxSize, maxPixels int) *ImageHandler {
+	return &ImageHandler{recipes: recipes, images: recipeImages, store: store, maxSize: maxSize, maxPixels: maxPixels}
+}
+
+func writeImageError(w http.ResponseWriter, status int, message string) {
+	response := Response{
+		Status:  "error",
+		Message: message,
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// findModifiableRecipe mencari recipe dan memastikan user boleh mengubahnya.
+// Response error sudah ditulis jika ok bernilai false.
+func (h *ImageHandler) findModifiableRecipe(w http.ResponseWriter, r *http.Request) (recipe models.Recipe, ok bool) {
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return recipe, false
+	}
+
+	recipe, err = h.recipes.FindByID(r.Context(), recipeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeImageError(w, http.StatusNotFound, "Recipe Not Found")
+			return recipe, false
+		}
+		writeImageError(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return recipe, false
+	}
+	if !canModifyRecipe(r, recipe) {
+		writeForbidden(w, "Only the author or an admin can modify this recipe")
+		return recipe, false
+	}
+	return recipe, true
+}
+
+// readImagePart membaca field "image" dari body multipart. Status bernilai
+// selain 0 jika terjadi error dan response belum ditulis.
+func (h *ImageHandler) readImagePart(w http.ResponseWriter, r *http.Request) ([]byte, int, string) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.maxSize)+multipartOverhead)
+	tooLarge := fmt.Sprintf("Image must not be larger than %d bytes", h.maxSize)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, http.StatusBadRequest, "Request must be multipart/form-data with an image field"
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, http.StatusBadRequest, "Missing image field"
+		}
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return nil, http.StatusRequestEntityTooLarge, tooLarge
+			}
+			return nil, http.StatusBadRequest, "Invalid multipart body: " + err.Error()
+		}
+		if part.FormName() != "image" {
+			continue
+		}
+		return readLimited(part, h.maxSize, tooLarge)
+	}
+}
+
+func readLimited(part *multipart.Part, maxSize int, tooLarge string) ([]byte, int, string) {
+	data, err := io.ReadAll(io.LimitReader(part, int64(maxSize)+1))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, http.StatusRequestEntityTooLarge, tooLarge
+		}
+		return nil, http.StatusBadRequest, "Error occurred while reading image: " + err.Error()
+	}
+	if len(data) > maxSize {
+		return nil, http.StatusRequestEntityTooLarge, tooLarge
+	}
+	if len(data) == 0 {
+		return nil, http.StatusBadRequest, "Image file is empty"
+	}
+	return data, 0, ""
+}
+
+// newImagePrefix membuat folder acak per unggahan sehingga URL gambar lama
+// dan baru tidak pernah sama dan aman di-cache selamanya.
+func newImagePrefix(recipeId uint) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("recipes/%d/%s/", recipeId, hex.EncodeToString(b)), nil
+}
+
+// deleteImages menghapus file gambar dari storage. Kegagalan hanya dicatat
+// karena data di database sudah konsisten.
+func (h *ImageHandler) deleteImages(ctx context.Context, recipeImages []models.RecipeImage) {
+	for _, image := range recipeImages {
+		if err := h.store.Delete(ctx, image.Key); err != nil {
+			log.Println("Gagal menghapus file gambar: ", err)
+		}
+	}
+}
+
+// UploadRecipeImage menerima gambar lewat field multipart "image", membuat
+// thumbnail, lalu mengganti gambar recipe. Format file
+// dikenali dari isinya, bukan dari Content-Type yang dikirim client.
+func (h *ImageHandler) UploadRecipeImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	data, status, message := h.readImagePart(w, r)
+	if status != 0 {
+		writeImageError(w, status, message)
+		return
+	}
+
+	variants, err := images.Process(data, h.maxPixels)
+	if err != nil {
+		switch {
+		case errors.Is(err, images.ErrUnsupportedFormat):
+			writeImageError(w, http.StatusUnsupportedMediaType, "Image must be JPEG, PNG, GIF or WebP")
+		case errors.Is(err, images.ErrTooManyPixels):
+			writeImageError(w, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, images.ErrInvalidImage):
+			writeImageError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeImageError(w, http.StatusInternalServerError, "Error occurred while processing image: "+err.Error())
+		}
+		return
+	}
+
+	prefix, err := newImagePrefix(recipe.ID)
+	if err != nil {
+		writeImageError(w, http.StatusInternalServerError, "Error occurred while storing image: "+err.Error())
+		return
+	}
+
+	// File yang sudah tersimpan dibersihkan lagi jika langkah berikutnya gagal
+	cleanupCtx := context.WithoutCancel(r.Context())
+	var stored []models.RecipeImage
+	imageURL := ""
+	for _, variant := range variants {
+		key := prefix + variant.Name + variant.Ext
+		url, err := h.store.Put(r.Context(), key, variant.ContentType, variant.Data)
+		if err != nil {
+			h.deleteImages(cleanupCtx, stored)
+			writeImageError(w, http.StatusInternalServerError, "Error occurred while storing image: "+err.Error())
+			return
+		}
+		if variant.Name == images.VariantOriginal {
+			imageURL = url
+		}
+		stored = append(stored, models.RecipeImage{
+			Variant:     variant.Name,
+			ContentType: variant.ContentType,
+			Key:         key,
+			URL:         url,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        len(variant.Data),
+		})
+	}
+
+	old, err := h.images.Replace(r.Context(), recipe.ID, imageURL, stored)
+	if err != nil {
+		h.deleteImages(cleanupCtx, stored)
+		if errors.Is(err, repository.ErrNotFound) {
+			writeImageError(w, http.StatusNotFound, "Recipe Not Found")
+			return
+		}
+		writeImageError(w, http.StatusInternalServerError, "Error occurred while saving image: "+err.Error())
+		return
+	}
+	h.deleteImages(cleanupCtx, old)
+
+	recipe, err = h.recipes.FindByID(r.Context(), recipe.ID)
+	if err != nil {
+		writeImageError(w, http.StatusInternalServerError, "Error occurred while retrieving data: "+err.Error())
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Image uploaded successfully",
+		Data:    recipe,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteRecipeImage menghapus semua gambar recipe dan mengosongkan ImageURL.
+func (h *ImageHandler) DeleteRecipeImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	old, err := h.images.Clear(r.Context(), recipe.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeImageError(w, http.StatusNotFound, "Recipe Not Found")
+			return
+		}
+		writeImageError(w, http.StatusInternalServerError, "Error occurred while deleting image: "+err.Error())
+		return
+	}
+	h.deleteImages(context.WithoutCancel(r.Context()), old)
+
+	response := Response{
+		Status:  "success",
+		Message: "Image has been deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/models"
+	"go-rest-modul/nutrition"
+	"go-rest-modul/repository"
+	"net/http"
+)
+
+type IngredientHandler struct {
+	ingredients repository.IngredientRepository
+	nutrients   repository.NutrientRepository
+}
+
+func NewIngredientHandler(ingredients repository.IngredientRepository, nutrients repository.NutrientRepository) *IngredientHandler {
+	return &IngredientHandler{ingredients: ingredients, nutrients: nutrients}
+}
+
+func (h *IngredientHandler) GetAllIngredient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredients, err := h.ingredients.List(r.Context())
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if len(ingredients) == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "Ingredient not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Ingredient retrieved successfully",
+		Data:    ingredients,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) GetIngredientbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	ingredient, err := h.ingredients.FindByID(r.Context(), ingredientId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Ingredient not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Ingredient retrieved successfully",
+		Data:    ingredient,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) CreateIngredient(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&ingredient); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if ingredient.Name == "" {
+		response := Response{
+			Status:  "error",
+			Message: "Name cannot be empty",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if ingredient.Density < 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Density cannot be negative",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if _, err := h.ingredients.FindByName(r.Context(), ingredient.Name); err == nil {
+		response := Response{
+			Status:  "error",
+			Message: "Ingredient already exists",
+		}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+		return
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		response := Response{
+			Status:  "error",
+			Message: "Failed to check ingredient: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Baris resep dibuat lewat endpoint recipe, bukan di sini
+	ingredient.RecipeIngredients = nil
+
+	if err := h.ingredients.Create(r.Context(), &ingredient); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error while creating data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Data created successfully",
+		Data:    ingredient,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) UpdateIngredient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	// Cari data lama
+	ingredient, err := h.ingredients.FindByID(r.Context(), ingredientId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "error",
+				Message: "Couldn't find ingredient",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Failed when checking data : " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Decode ke struct baru
+	var input struct {
+		Name    string   `json:"name"`
+		Density *float64 `json:"density"` // pointer untuk optional field
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error while decoding",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Name boleh kosong jika hanya density yang diubah
+	if input.Name == "" && input.Density == nil {
+		response := Response{
+			Status:  "error",
+			Message: "Name cannot be empty",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if input.Density != nil && *input.Density < 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Density cannot be negative",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Cek duplikasi nama (jika nama berubah)
+	if input.Name != "" && input.Name != ingredient.Name {
+		if _, err := h.ingredients.FindByName(r.Context(), input.Name); err == nil {
+			response := Response{
+				Status:  "error",
+				Message: "Ingredient name already exists",
+			}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(response)
+			return
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "error",
+				Message: "Failed to check ingredient: " + err.Error(),
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	// Update field yang diizinkan
+	if input.Name != "" {
+		ingredient.Name = input.Name
+	}
+	if input.Density != nil {
+		ingredient.Density = *input.Density
+	}
+
+	if err := h.ingredients.Update(r.Context(), &ingredient); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "error when update: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Ingredient has been updated",
+		Data:    ingredient,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) DeleteIngredient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if _, err := h.ingredients.FindByID(r.Context(), ingredientId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "error",
+				Message: "Ingredient Not Found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error Occure while searching data : " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Tolak hapus jika bahan masih dipakai resep
+	used, err := h.ingredients.CountUsage(r.Context(), ingredientId)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error Occure while checking usage : " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	if used > 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Ingredient is still used by recipes",
+		}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if err := h.ingredients.Delete(r.Context(), ingredientId); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "An error occured while deleting ingredient" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Ingredient has been deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) GetRecipesByIngredient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	recipes, err := h.ingredients.ListRecipes(r.Context(), ingredientId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Ingredient not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if len(recipes) == 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Recipe Not Found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe by Ingredient Found",
+		Data:    recipes,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IngredientHandler) GetIngredientNutrients(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	nutrient, err := h.nutrients.FindByIngredient(r.Context(), ingredientId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Nutrient data not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Nutrient data retrieved successfully",
+		Data:    nutrient,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateIngredientNutrients mengisi data gizi per 100 g secara manual,
+// misalnya untuk bahan yang tidak ada di dump FoodData Central.
+func (h *IngredientHandler) UpdateIngredientNutrients(w http.ResponseWriter, r *http.Request) {
+	var input nutrition.Facts
+
+	w.Header().Set("Content-Type", "application/json")
+
+	ingredientId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if _, err := h.ingredients.FindByID(r.Context(), ingredientId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Ingredient not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if input.HasNegative() {
+		response := Response{
+			Status:  "error",
+			Message: "Nutrient values cannot be negative",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	nutrient := models.IngredientNutrient{IngredientId: ingredientId}
+	input.Apply(&nutrient)
+	if err := h.nutrients.Save(r.Context(), &nutrient); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Failed to update nutrient data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Nutrient data updated successfully",
+		Data:    nutrient,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,524 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"go-rest-modul/units"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type MealPlanHandler struct {
+	plans repository.MealPlanRepository
+	lists repository.ShoppingListRepository
+}
+
+func NewMealPlanHandler(plans repository.MealPlanRepository, lists repository.ShoppingListRepository) *MealPlanHandler {
+	return &MealPlanHandler{plans: plans, lists: lists}
+}
+
+// MealPlanWeek adalah tampilan tujuh hari sebuah meal plan.
+type MealPlanWeek struct {
+	MealPlanId uint          `json:"meal_plan_id"`
+	Name       string        `json:"name"`
+	Start      string        `json:"start"`
+	End        string        `json:"end"`
+	Days       []MealPlanDay `json:"days"`
+}
+
+// MealPlanDay berisi entry satu hari yang dikelompokkan per slot makan.
+type MealPlanDay struct {
+	Date    string                            `json:"date"`
+	Weekday string                            `json:"weekday"`
+	Meals   map[string][]models.MealPlanEntry `json:"meals"`
+}
+
+// writeMealPlanError menangani error repository yang umum pada endpoint
+// meal plan: data tidak ditemukan dan entry yang tidak valid.
+func writeMealPlanError(w http.ResponseWriter, err error, notFound string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		response := Response{
+			Status:  "not found",
+			Message: notFound,
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	if errors.Is(err, repository.ErrInvalidMealEntry) {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	response := Response{
+		Status:  "error",
+		Message: "error occured: " + err.Error(),
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeInvalidDate(w http.ResponseWriter, field string) {
+	response := Response{
+		Status:  "error",
+		Message: field + " must use format " + repository.DateLayout,
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) GetAllMealPlan(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plans, total, err := h.plans.List(r.Context(), principal.UserId, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "Meal plan not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(plans) > 0 {
+		lastID = plans[len(plans)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Meal plan retrieved successfully",
+		Data:       plans,
+		Pagination: newPagination(r, opts, total, len(plans), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) GetMealPlanbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plan, err := h.plans.FindByID(r.Context(), principal.UserId, planId)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan retrieved successfully",
+		Data:    plan,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) CreateMealPlan(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name      string                 `json:"name"`
+		StartDate string                 `json:"start_date"`
+		Entries   []repository.MealEntry `json:"entries"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		response := Response{
+			Status:  "error",
+			Message: "Name cannot be empty",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	startDate, err := repository.ParseDate(input.StartDate)
+	if err != nil {
+		writeInvalidDate(w, "start_date")
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plan := models.MealPlan{UserId: principal.UserId, Name: strings.TrimSpace(input.Name), StartDate: startDate}
+	if err := h.plans.Create(r.Context(), &plan, input.Entries); err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan created successfully",
+		Data:    plan,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) UpdateMealPlan(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name      *string `json:"name"`
+		StartDate *string `json:"start_date"`
+		// nil berarti entry tidak diubah, slice kosong berarti dihapus semua
+		Entries *[]repository.MealEntry `json:"entries"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	update := repository.MealPlanUpdate{Entries: input.Entries}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			response := Response{
+				Status:  "error",
+				Message: "Name cannot be empty",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		update.Name = &name
+	}
+	if input.StartDate != nil {
+		startDate, err := repository.ParseDate(*input.StartDate)
+		if err != nil {
+			writeInvalidDate(w, "start_date")
+			return
+		}
+		update.StartDate = &startDate
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plan, err := h.plans.Update(r.Context(), principal.UserId, planId, update)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan updated successfully",
+		Data:    plan,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) DeleteMealPlan(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.plans.Delete(r.Context(), principal.UserId, planId); err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) AddMealPlanEntry(w http.ResponseWriter, r *http.Request) {
+	var input repository.MealEntry
+
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	entry, err := h.plans.AddEntry(r.Context(), principal.UserId, planId, input)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan entry created successfully",
+		Data:    entry,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MealPlanHandler) DeleteMealPlanEntry(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+	entryId, err := parseID(r, "entry_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.plans.DeleteEntry(r.Context(), principal.UserId, planId, entryId); err != nil {
+		writeMealPlanError(w, err, "Meal plan entry not found")
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan entry deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetMealPlanWeek menampilkan tujuh hari mulai dari parameter start, atau
+// dari tanggal mulai meal plan jika start kosong.
+func (h *MealPlanHandler) GetMealPlanWeek(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plan, err := h.plans.FindByID(r.Context(), principal.UserId, planId)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	start := plan.StartDate
+	if param := r.URL.Query().Get("start"); param != "" {
+		start, err = repository.ParseDate(param)
+		if err != nil {
+			writeInvalidDate(w, "start")
+			return
+		}
+	}
+	end := start.AddDate(0, 0, 7)
+
+	entries, err := h.plans.ListEntries(r.Context(), principal.UserId, planId, start, end)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	week := MealPlanWeek{
+		MealPlanId: plan.ID,
+		Name:       plan.Name,
+		Start:      start.Format(repository.DateLayout),
+		End:        end.AddDate(0, 0, -1).Format(repository.DateLayout),
+	}
+	for day := 0; day < 7; day++ {
+		date := start.AddDate(0, 0, day)
+		meals := make(map[string][]models.MealPlanEntry, len(repository.MealSlots))
+		for _, slot := range repository.MealSlots {
+			meals[slot] = []models.MealPlanEntry{}
+		}
+		for _, entry := range entries {
+			// Recipe yang sudah dihapus tidak ditampilkan
+			if entry.Recipe.ID == 0 {
+				continue
+			}
+			if entry.Date.Format(repository.DateLayout) == date.Format(repository.DateLayout) {
+				meals[entry.Slot] = append(meals[entry.Slot], entry)
+			}
+		}
+		week.Days = append(week.Days, MealPlanDay{
+			Date:    date.Format(repository.DateLayout),
+			Weekday: date.Weekday().String(),
+			Meals:   meals,
+		})
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Meal plan week retrieved successfully",
+		Data:    week,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateMealPlanShoppingList membuat daftar belanja dari semua entry meal
+// plan. Recipe yang sudah dihapus dilewati.
+func (h *MealPlanHandler) CreateMealPlanShoppingList(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name  string `json:"name"`
+		Units string `json:"units"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	planId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	// Body boleh kosong
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var system units.System
+	if input.Units != "" {
+		parsed, err := units.ParseSystem(input.Units)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		system = parsed
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	plan, err := h.plans.FindByID(r.Context(), principal.UserId, planId)
+	if err != nil {
+		writeMealPlanError(w, err, "Meal plan not found")
+		return
+	}
+
+	var selections []repository.RecipeSelection
+	for _, entry := range plan.Entries {
+		if entry.Recipe.ID == 0 {
+			continue
+		}
+		selections = append(selections, repository.RecipeSelection{
+			RecipeId: entry.RecipeId,
+			Servings: entry.Servings,
+		})
+	}
+	if len(selections) == 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Meal plan has no recipes",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if input.Name == "" {
+		input.Name = plan.Name
+	}
+
+	list, err := h.lists.Create(r.Context(), repository.NewShoppingList{
+		UserId:  principal.UserId,
+		Name:    input.Name,
+		Recipes: selections,
+		System:  system,
+	})
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Failed to create shopping list: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Shopping list created successfully",
+		Data:    list,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"go-rest-modul/repository"
+	"net/http"
+	"strconv"
+)
+
+// Pagination ditambahkan pada Response untuk endpoint list.
+type Pagination struct {
+	Total    int64  `json:"total"`
+	Page     int    `json:"page,omitempty"`
+	PageSize int    `json:"page_size"`
+	Sort     string `json:"sort,omitempty"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
+
+// parseListOptions membaca parameter page, page_size, after dan sort.
+func parseListOptions(r *http.Request) (repository.ListOptions, error) {
+	params := r.URL.Query()
+	var opts repository.ListOptions
+
+	if page := params.Get("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return opts, errors.New("page must be a positive number")
+		}
+		opts.Page = n
+	}
+	if pageSize := params.Get("page_size"); pageSize != "" {
+		n, err := strconv.Atoi(pageSize)
+		if err != nil || n < 1 {
+			return opts, errors.New("page_size must be a positive number")
+		}
+		opts.PageSize = n
+	}
+	if after := params.Get("after"); after != "" {
+		n, err := strconv.ParseUint(after, 10, 64)
+		if err != nil || n == 0 {
+			return opts, errors.New("after must be a valid ID")
+		}
+		if opts.Page != 0 {
+			return opts, errors.New("page and after cannot be combined")
+		}
+		opts.After = uint(n)
+	}
+	opts.Sort = params.Get("sort")
+
+	return opts.Normalize(), nil
+}
+
+// isListOptionsError bernilai true untuk error repository yang berasal dari
+// parameter pagination/sort yang tidak valid.
+func isListOptionsError(err error) bool {
+	return errors.Is(err, repository.ErrInvalidSort) || errors.Is(err, repository.ErrInvalidCursor)
+}
+
+// newPagination menyusun metadata pagination beserta link next/prev. lastID
+// adalah ID item terakhir di halaman ini dan count jumlah item di halaman ini.
+func newPagination(r *http.Request, opts repository.ListOptions, total int64, count int, lastID uint) *Pagination {
+	pagination := &Pagination{
+		Total:    total,
+		PageSize: opts.PageSize,
+		Sort:     opts.Sort,
+	}
+
+	link := func(key, value string) string {
+		u := *r.URL
+		query := u.Query()
+		query.Del("page")
+		query.Del("after")
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	if opts.After != 0 {
+		// Mode cursor hanya bisa maju
+		if count == opts.PageSize && count > 0 {
+			pagination.Next = link("after", strconv.FormatUint(uint64(lastID), 10))
+		}
+		return pagination
+	}
+
+	pagination.Page = opts.Page
+	if int64(opts.Page*opts.PageSize) < total {
+		pagination.Next = link("page", strconv.Itoa(opts.Page+1))
+	}
+	if opts.Page > 1 {
+		pagination.Prev = link("page", strconv.Itoa(opts.Page-1))
+	}
+	return pagination
+}
@@ -3,32 +3,120 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"go-rest-modul/database"
+	"fmt"
+	"go-rest-modul/auth"
 	"go-rest-modul/models"
+	"go-rest-modul/nutrition"
+	"go-rest-modul/repository"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
-	"gorm.io/gorm"
 	"strconv"
 )
 
 type Response struct {
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Status     string      `json:"status"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
-func ReadAllHandler(w http.ResponseWriter, r *http.Request) {
+// parseID membaca path variable key sebagai ID numerik.
+func parseID(r *http.Request, key string) (uint, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)[key], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// writeInvalidID menulis response standar untuk ID yang tidak valid.
+func writeInvalidID(w http.ResponseWriter) {
+	response := Response{
+		Status:  "error",
+		Message: "Invalid ID",
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+// canModifyRecipe bernilai true jika user yang login adalah author recipe
+// atau boleh memoderasi recipe. Recipe lama tanpa author hanya bisa diubah
+// moderator.
+func canModifyRecipe(r *http.Request, recipe models.Recipe) bool {
+	principal, ok := auth.FromContext(r.Context())
+	return ok && (principal.Can(auth.PermRecipeModerate) || principal.Owns(recipe.AuthorId))
+}
+
+// writeInvalidListOptions menulis response standar untuk parameter pagination yang tidak valid.
+func writeInvalidListOptions(w http.ResponseWriter, err error) {
+	response := Response{
+		Status:  "error",
+		Message: "Invalid list parameter: " + err.Error(),
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+func lastRecipeID(recipes []models.Recipe) uint {
+	if len(recipes) == 0 {
+		return 0
+	}
+	return recipes[len(recipes)-1].ID
+}
+
+type RecipeHandler struct {
+	recipes    repository.RecipeRepository
+	categories repository.CategoryRepository
+	nutrients  repository.NutrientRepository
+}
+
+func NewRecipeHandler(recipes repository.RecipeRepository, categories repository.CategoryRepository, nutrients repository.NutrientRepository) *RecipeHandler {
+	return &RecipeHandler{recipes: recipes, categories: categories, nutrients: nutrients}
+}
+
+// RecipeDetail adalah recipe beserta perhitungan gizinya.
+type RecipeDetail struct {
+	models.Recipe
+	Nutrition nutrition.Report `json:"nutrition"`
+}
+
+func (h *RecipeHandler) ReadAllHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var recipes []models.Recipe
 
-	err := database.DB.
-		Preload("Category").
-		Preload("RecipeIngredients").
-		Preload("RecipeIngredients.Ingredient").
-		Find(&recipes).Error
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+	system, localize, err := parseUnitSystem(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	rating, err := parseRatingFilter(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
 
+	recipes, total, err := h.recipes.List(r.Context(), rating, opts)
 	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "Fail to Query, Error : " + err.Error(),
@@ -38,7 +126,7 @@ func ReadAllHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(recipes) == 0 {
+	if total == 0 {
 		response := Response{
 			Status:  "success",
 			Message: "Recipe Not Found",
@@ -48,22 +136,50 @@ func ReadAllHandler(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 		return
 	}
+	if localize {
+		localizeRecipes(recipes, system)
+	}
+
 	response := Response{
-		Status:  "success",
-		Message: "All Recipe Retrieved Successfully",
-		Data:    recipes,
+		Status:     "success",
+		Message:    "All Recipe Retrieved Successfully",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), lastRecipeID(recipes)),
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func ReadbyIDHandler(w http.ResponseWriter, r *http.Request) {
-	var recipeid = mux.Vars(r)["id"]
-	var recipe models.Recipe
+func (h *RecipeHandler) ReadbyIDHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	err := database.DB.First(&recipe, recipeid).Error
+	recipeid, err := parseID(r, "id")
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		writeInvalidID(w)
+		return
+	}
+	system, localize, err := parseUnitSystem(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	format, ok := recipeFormat(r)
+	if !ok {
+		response := Response{
+			Status:  "error",
+			Message: "format must be json, jsonld or html",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	recipe, err := h.recipes.FindByID(r.Context(), recipeid)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "error",
 				Message: "Receip Not Found",
@@ -80,31 +196,184 @@ func ReadbyIDHandler(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 		return
 	}
+	if format != formatJSON {
+		if localize {
+			localizeRecipe(&recipe, system)
+		}
+		writeRecipeDocument(w, recipe, format)
+		return
+	}
+
+	// Hitung gizi dari amount asli sebelum satuannya diubah
+	ingredientIds := make([]uint, 0, len(recipe.RecipeIngredients))
+	for _, line := range recipe.RecipeIngredients {
+		ingredientIds = append(ingredientIds, line.IngredientId)
+	}
+	table, err := h.nutrients.FindByIngredients(r.Context(), ingredientIds)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while retrieving data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	detail := RecipeDetail{Nutrition: nutrition.Calculate(recipe, table)}
+
+	if localize {
+		localizeRecipe(&recipe, system)
+	}
+	detail.Recipe = recipe
 	response := Response{
 		Status:  "success",
 		Message: "Receipt Retrieved Successfully",
-		Data:    recipe,
+		Data:    detail,
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func AddRecipeHandler(w http.ResponseWriter, r *http.Request) {
+func (h *RecipeHandler) ScaleRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var recipe models.Recipe
-	err := json.NewDecoder(r.Body).Decode(&recipe)
+	params := r.URL.Query()
+
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+	system, _, err := parseUnitSystem(r)
 	if err != nil {
 		response := Response{
 			Status:  "error",
-			Message: "Error occured while decoding data :" + err.Error(),
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	servings, factor := params.Get("servings"), params.Get("factor")
+	if (servings == "") == (factor == "") {
+		response := Response{
+			Status:  "error",
+			Message: "Provide either servings or factor",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	recipe, err := h.recipes.FindByID(r.Context(), recipeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "error",
+				Message: "Recipe Not Found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while retrieving data :" + err.Error(),
 		}
+		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var scale float64
+	if servings != "" {
+		target, err := strconv.Atoi(servings)
+		if err != nil || target < 1 {
+			response := Response{
+				Status:  "error",
+				Message: "servings must be a positive number",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		if recipe.Servings < 1 {
+			response := Response{
+				Status:  "error",
+				Message: "Recipe has no servings, use factor instead",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		scale = float64(target) / float64(recipe.Servings)
+	} else {
+		scale, err = strconv.ParseFloat(factor, 64)
+		// Ditulis sebagai !(scale > 0) agar NaN ikut ditolak
+		if err != nil || !(scale > 0) {
+			response := Response{
+				Status:  "error",
+				Message: "factor must be a positive number",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+	if scale > maxScaleFactor || float64(recipe.Servings)*scale > maxScaledServings {
+		response := Response{
+			Status:  "error",
+			Message: fmt.Sprintf("Scaled recipe is too large: factor is limited to %d and servings to %d", maxScaleFactor, maxScaledServings),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Recipe Scaled Successfully",
+		Data:    scaleRecipe(recipe, scale, system),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecipeHandler) AddRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var input struct {
+		models.Recipe
+		Ingredients []repository.IngredientLine `json:"ingredients"`
+		// Nama tag; tag yang belum ada akan dibuat
+		TagNames []string `json:"tags"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while decoding data :" + err.Error(),
+		}
 		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
 		return
 	}
+	// Hanya field yang boleh diisi client yang disalin. ID, rating dan gambar
+	// dari payload diabaikan, baris bahan dan tag hanya diterima lewat field
+	// ingredients dan tags, dan author selalu user yang sedang login.
+	principal, _ := auth.FromContext(r.Context())
+	recipe := models.Recipe{
+		Title:        input.Title,
+		Descriptions: input.Descriptions,
+		Instructions: input.Instructions,
+		PrepTime:     input.PrepTime,
+		CookTime:     input.CookTime,
+		Servings:     input.Servings,
+		ImageURL:     input.ImageURL,
+		CategoryId:   input.CategoryId,
+		AuthorId:     &principal.UserId,
+	}
 
-	var categoryExist models.Category
-	if err := database.DB.First(&categoryExist, recipe.CategoryId).Error; err != nil {
+	if _, err := h.categories.FindByID(r.Context(), recipe.CategoryId); err != nil {
 		response := Response{
 			Status:  "error",
 			Message: "Category Not Found",
@@ -114,16 +383,26 @@ func AddRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DB.Create(&recipe).Error
+	err = h.recipes.Create(r.Context(), &recipe, input.Ingredients, input.TagNames)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidIngredientLine) || errors.Is(err, repository.ErrInvalidTag) {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "Error occured while creating data :" + err.Error(),
 		}
-		json.NewEncoder(w).Encode(response)
 		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
 		return
 	}
+
 	response := Response{
 		Status:  "success",
 		Message: "Receipt Created Successfully",
@@ -133,25 +412,20 @@ func AddRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
+func (h *RecipeHandler) UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var recipeId = mux.Vars(r)["id"]
-	var existingRecipe models.Recipe
 
 	// Validasi parameter ID
-	if recipeId == "" {
-		response := Response{
-			Status:  "error",
-			Message: "Recipe ID is required",
-		}
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
 		return
 	}
 
 	// Cari recipe yang akan diupdate
-	if err := database.DB.First(&existingRecipe, recipeId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	existing, err := h.recipes.FindByID(r.Context(), recipeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "error",
 				Message: "Recipe Not Found",
@@ -168,6 +442,10 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 		return
 	}
+	if !canModifyRecipe(r, existing) {
+		writeForbidden(w, "Only the author or an admin can modify this recipe")
+		return
+	}
 
 	// Struct untuk input yang aman
 	var input struct {
@@ -179,6 +457,10 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		Servings     *int   `json:"servings"`  // pointer untuk optional field
 		ImageURL     string `json:"image_url"`
 		CategoryId   *uint  `json:"category_id"` // pointer untuk optional field
+		// nil berarti baris bahan tidak diubah, slice kosong berarti dihapus semua
+		Ingredients *[]repository.IngredientLine `json:"ingredients"`
+		// nil berarti tag tidak diubah, slice kosong berarti semua tag dilepas
+		Tags *[]string `json:"tags"`
 	}
 
 	// Decode request body ke input struct
@@ -194,9 +476,8 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Validasi CategoryId jika ada
 	if input.CategoryId != nil && *input.CategoryId != 0 {
-		var categoryExists models.Category
-		if err := database.DB.First(&categoryExists, *input.CategoryId).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+		if _, err := h.categories.FindByID(r.Context(), *input.CategoryId); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
 				response := Response{
 					Status:  "error",
 					Message: "Category Not Found",
@@ -216,35 +497,35 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update field yang diizinkan secara selektif
-	updates := make(map[string]interface{})
+	update := repository.RecipeUpdate{
+		PrepTime:    input.PrepTime,
+		CookTime:    input.CookTime,
+		Servings:    input.Servings,
+		Ingredients: input.Ingredients,
+		Tags:        input.Tags,
+	}
+	// Editor dicatat pada revisi yang dibuat sebelum perubahan
+	principal, _ := auth.FromContext(r.Context())
+	update.EditorId = &principal.UserId
 
 	if input.Title != "" {
-		updates["title"] = input.Title
+		update.Title = &input.Title
 	}
 	if input.Descriptions != "" {
-		updates["descriptions"] = input.Descriptions
+		update.Descriptions = &input.Descriptions
 	}
 	if input.Instructions != "" {
-		updates["instructions"] = input.Instructions
-	}
-	if input.PrepTime != nil {
-		updates["prep_time"] = *input.PrepTime
-	}
-	if input.CookTime != nil {
-		updates["cook_time"] = *input.CookTime
-	}
-	if input.Servings != nil {
-		updates["servings"] = *input.Servings
+		update.Instructions = &input.Instructions
 	}
 	if input.ImageURL != "" {
-		updates["image_url"] = input.ImageURL
+		update.ImageURL = &input.ImageURL
 	}
 	if input.CategoryId != nil && *input.CategoryId != 0 {
-		updates["category_id"] = *input.CategoryId
+		update.CategoryId = input.CategoryId
 	}
 
 	// Validasi minimal ada field yang diupdate
-	if len(updates) == 0 {
+	if update.IsEmpty() {
 		response := Response{
 			Status:  "error",
 			Message: "No valid fields provided for update",
@@ -254,22 +535,21 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Lakukan update
-	if err := database.DB.Model(&existingRecipe).Updates(updates).Error; err != nil {
-		response := Response{
-			Status:  "error",
-			Message: "Error occurred while updating data: " + err.Error(),
+	// Lakukan update field dan baris bahan dalam satu transaksi
+	existingRecipe, err := h.recipes.Update(r.Context(), recipeId, update)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidIngredientLine) || errors.Is(err, repository.ErrInvalidTag) {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
 		}
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	// Reload data untuk mendapatkan data terbaru
-	if err := database.DB.Preload("Category").First(&existingRecipe, recipeId).Error; err != nil {
 		response := Response{
 			Status:  "error",
-			Message: "Error loading updated data: " + err.Error(),
+			Message: "Error occurred while updating data: " + err.Error(),
 		}
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(response)
@@ -285,12 +565,16 @@ func UpdateRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func DeleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
-	var recipeId = mux.Vars(r)["id"]
-	var recipe models.Recipe
+func (h *RecipeHandler) DeleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	if err := database.DB.First(&recipe, recipeId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+	recipe, err := h.recipes.FindByID(r.Context(), recipeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			response := Response{
 				Status:  "error",
 				Message: "Recipe Not Found",
@@ -307,8 +591,12 @@ func DeleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 		return
 	}
+	if !canModifyRecipe(r, recipe) {
+		writeForbidden(w, "Only the author or an admin can delete this recipe")
+		return
+	}
 
-	if err := database.DB.Delete(&recipe).Error; err != nil {
+	if err := h.recipes.Delete(r.Context(), recipe.ID); err != nil {
 		response := Response{
 			Status:  "error",
 			Message: "Error occured while deleting data :" + err.Error(),
@@ -327,18 +615,40 @@ func DeleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func SearchRecipeHandler(w http.ResponseWriter, r *http.Request) {
+func (h *RecipeHandler) SearchRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	var query = r.URL.Query().Get("q")
-	var recipes []models.Recipe
 	w.Header().Set("Content-Type", "application/json")
-	if err := database.DB.
-		Preload("Category").
-		Preload("RecipeIngredients").
-		Preload("RecipeIngredients.Ingredient").Preload("RecipeIngredients.Recipe").
-		Where("title LIKE ?", "%"+query+"%").
-		Or("descriptions LIKE ?", "%"+query+"%").
-		Or("instructions LIKE ?", "%"+query+"%").
-		Find(&recipes).Error; err != nil {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+	system, localize, err := parseUnitSystem(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	rating, err := parseRatingFilter(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	recipes, total, err := h.recipes.Search(r.Context(), query, rating, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "Error occurred while searching data :" + err.Error(),
@@ -348,7 +658,7 @@ func SearchRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(recipes) == 0 {
+	if total == 0 {
 		response := Response{
 			Status:  "error",
 			Message: "Recipe Not Found",
@@ -358,43 +668,90 @@ func SearchRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if localize {
+		for i := range recipes {
+			localizeRecipe(&recipes[i].Recipe, system)
+		}
+	}
+
+	var lastID uint
+	if len(recipes) > 0 {
+		lastID = recipes[len(recipes)-1].ID
+	}
+
 	response := Response{
-		Status:  "success",
-		Message: "Search Succeed",
-		Data:    recipes,
+		Status:     "success",
+		Message:    "Search Succeed",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), lastID),
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func FilterRecipesHandler(w http.ResponseWriter, r *http.Request) {
+func (h *RecipeHandler) FilterRecipesHandler(w http.ResponseWriter, r *http.Request) {
 	params := r.URL.Query()
 
-	var recipes []models.Recipe
-
 	w.Header().Set("Content-Type", "application/json")
 
-	db := database.DB.Model(&models.Recipe{}).Preload("Category")
-
-	if category := params.Get("category"); category != "" {
-		db = db.Joins("JOIN categories ON categories.id = recipes.category_id").Where("categories.name = ?", category)
+	filter := repository.RecipeFilter{
+		Category: params.Get("category"),
 	}
 
 	if maxpreptime := params.Get("max_preptime"); maxpreptime != "" {
 		maxpreptime, err := strconv.Atoi(maxpreptime)
 		if err == nil {
-			db = db.Where("prep_time <= ?", maxpreptime)
+			filter.MaxPrepTime = &maxpreptime
 		}
 	}
 
 	if servings := params.Get("servings"); servings != "" {
 		servings, err := strconv.Atoi(servings)
 		if err == nil {
-			db = db.Where("servings = ?", servings)
+			filter.Servings = &servings
+		}
+	}
+
+	// tags: semua harus ada, any_tags: minimal satu, exclude_tags: tidak boleh ada
+	filter.TagFilter = repository.TagFilter{
+		AllOf:  splitList(params["tags"]),
+		AnyOf:  splitList(params["any_tags"]),
+		NoneOf: splitList(params["exclude_tags"]),
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	system, localize, err := parseUnitSystem(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	filter.RatingFilter, err = parseRatingFilter(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
 		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
 	}
 
-	if err := db.Find(&recipes).Error; err != nil {
+	recipes, total, err := h.recipes.Filter(r.Context(), filter, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "Error occurred while filtering data :" + err.Error(),
@@ -404,7 +761,7 @@ func FilterRecipesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(recipes) == 0 {
+	if total == 0 {
 		response := Response{
 			Status:  "error",
 			Message: "Recipe Not Found",
@@ -414,24 +771,83 @@ func FilterRecipesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if localize {
+		localizeRecipes(recipes, system)
+	}
+
 	response := Response{
-		Status:  "success",
-		Message: "Filter Succeed",
-		Data:    recipes,
+		Status:     "success",
+		Message:    "Filter Succeed",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), lastRecipeID(recipes)),
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-func FilterByCategoryHandler(w http.ResponseWriter, r *http.Request) {
-	var categoryId = mux.Vars(r)["category_id"]
-	var recipes []models.Recipe
-
+func (h *RecipeHandler) FilterByCategoryHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	db := database.DB.Model(&models.Recipe{}).Preload("Category")
+	categoryId, err := parseID(r, "category_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
 
-	if err := db.Find(&recipes, "category_id = ?", categoryId).Error; err != nil {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	system, localize, err := parseUnitSystem(r)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// include_descendants=true ikut mengambil recipe dari semua sub-category
+	categoryIds := []uint{categoryId}
+	if value := r.URL.Query().Get("include_descendants"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "include_descendants must be true or false",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		if include {
+			categoryIds, err = h.categories.DescendantIDs(r.Context(), categoryId)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				response := Response{
+					Status:  "error",
+					Message: "error occurred : " + err.Error(),
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+			// Category yang tidak ada tetap dijawab "Recipe Not Found" di bawah
+			if err != nil {
+				categoryIds = []uint{categoryId}
+			}
+		}
+	}
+
+	recipes, total, err := h.recipes.ListByCategory(r.Context(), categoryIds, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
 		response := Response{
 			Status:  "error",
 			Message: "error occurred : " + err.Error(),
@@ -441,7 +857,7 @@ func FilterByCategoryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(recipes) == 0 {
+	if total == 0 {
 		response := Response{
 			Status:  "error",
 			Message: "Recipe Not Found",
@@ -451,10 +867,166 @@ func FilterByCategoryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if localize {
+		localizeRecipes(recipes, system)
+	}
+
 	response := Response{
-		Status:  "success",
-		Message: "Recipe by Category Found",
-		Data:    recipes,
+		Status:     "success",
+		Message:    "Recipe by Category Found",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), lastRecipeID(recipes)),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// splitList membaca parameter yang boleh diulang atau dipisah koma,
+// misalnya ?ingredients=garam,gula&ingredients=telur.
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func (h *RecipeHandler) CookableRecipesHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+	if opts.After != 0 || opts.Sort != "" {
+		writeInvalidListOptions(w, errors.New("cookable recipes only support page and page_size"))
+		return
+	}
+
+	query := repository.CookableQuery{
+		IngredientNames: splitList(params["ingredients"]),
+		MaxMissing:      2,
+	}
+
+	for _, id := range splitList(params["ingredient_ids"]) {
+		ingredientId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "Invalid ingredient_ids: " + id,
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		query.IngredientIds = append(query.IngredientIds, uint(ingredientId))
+	}
+
+	if len(query.IngredientIds) == 0 && len(query.IngredientNames) == 0 {
+		response := Response{
+			Status:  "error",
+			Message: "ingredients or ingredient_ids is required",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if maxMissing := params.Get("max_missing"); maxMissing != "" {
+		maxMissing, err := strconv.Atoi(maxMissing)
+		if err != nil || maxMissing < 0 {
+			response := Response{
+				Status:  "error",
+				Message: "max_missing must be zero or a positive number",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		query.MaxMissing = maxMissing
+	}
+
+	recipes, total, err := h.recipes.ListCookable(r.Context(), query, opts)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occurred while searching data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Recipe Not Found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Cookable Recipes Found",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), 0),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// MyRecipesHandler menampilkan recipe yang dibuat oleh user yang sedang login.
+func (h *RecipeHandler) MyRecipesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	filter := repository.RecipeFilter{AuthorId: &principal.UserId}
+	recipes, total, err := h.recipes.Filter(r.Context(), filter, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Fail to Query, Error : " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "You Have No Recipes Yet",
+			Data:    recipes,
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "My Recipes Retrieved Successfully",
+		Data:       recipes,
+		Pagination: newPagination(r, opts, total, len(recipes), lastRecipeID(recipes)),
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
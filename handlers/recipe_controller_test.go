@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+// newRecipeTestHandler membuat RecipeHandler dengan recipe awal milik user 1
+// dan satu category dengan id 1.
+func newRecipeTestHandler() (*RecipeHandler, *fakeRecipeRepository) {
+	recipes := newFakeRecipeRepository(models.Recipe{
+		Model:      gorm.Model{ID: 1},
+		Title:      "Soto Ayam",
+		Servings:   4,
+		CategoryId: 1,
+		AuthorId:   uintPtr(1),
+	})
+	categories := newFakeCategoryRepository(models.Category{Model: gorm.Model{ID: 1}, Name: "Soup"})
+	return NewRecipeHandler(recipes, categories, fakeNutrientRepository{}), recipes
+}
+
+// newRecipeRequest membuat request dengan path variable dan principal. vars
+// dan principal boleh nil.
+func newRecipeRequest(method, target, body string, vars map[string]string, principal *auth.Principal) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if vars != nil {
+		r = mux.SetURLVars(r, vars)
+	}
+	if principal != nil {
+		r = r.WithContext(auth.NewContext(r.Context(), *principal))
+	}
+	return r
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, data interface{}) Response {
+	t.Helper()
+	var response Response
+	if data != nil {
+		response.Data = data
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return response
+}
+
+var (
+	testAuthor = &auth.Principal{UserId: 1, Username: "author", Role: models.RoleEditor}
+	testOther  = &auth.Principal{UserId: 2, Username: "other", Role: models.RoleEditor}
+	testAdmin  = &auth.Principal{UserId: 3, Username: "admin", Role: models.RoleAdmin}
+)
+
+func TestAddRecipeHandler(t *testing.T) {
+	h, recipes := newRecipeTestHandler()
+
+	body := `{"Title":"Rendang","CategoryId":1,"AuthorId":99,
+		"ingredients":[{"name":"Beef","amount":"1","unit":"kg"}],"tags":["spicy"]}`
+	w := httptest.NewRecorder()
+	h.AddRecipeHandler(w, newRecipeRequest(http.MethodPost, "/api/recipe", body, nil, testOther))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created models.Recipe
+	decodeResponse(t, w, &created)
+	if created.ID == 0 || created.Title != "Rendang" {
+		t.Fatalf("created = %+v", created)
+	}
+	stored, err := recipes.FindByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("recipe %d not stored: %v", created.ID, err)
+	}
+	if !testOther.Owns(stored.AuthorId) {
+		t.Errorf("AuthorId = %v, want the logged-in user %d", stored.AuthorId, testOther.UserId)
+	}
+	if len(stored.RecipeIngredients) != 1 || len(stored.Tags) != 1 {
+		t.Errorf("ingredients = %d, tags = %d, want 1 and 1", len(stored.RecipeIngredients), len(stored.Tags))
+	}
+}
+
+func TestAddRecipeHandlerIgnoresServerFields(t *testing.T) {
+	h, recipes := newRecipeTestHandler()
+
+	body := `{"ID":1,"Title":"Rendang","CategoryId":1,"RatingAverage":5,"RatingCount":999,
+		"Images":[{"Variant":"original","URL":"/media/x.jpg"}],
+		"RecipeIngredients":[{"IngredientId":1}],"Tags":[{"Name":"injected"}]}`
+	w := httptest.NewRecorder()
+	h.AddRecipeHandler(w, newRecipeRequest(http.MethodPost, "/api/recipe", body, nil, testAuthor))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created models.Recipe
+	decodeResponse(t, w, &created)
+	stored, err := recipes.FindByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("recipe %d not stored: %v", created.ID, err)
+	}
+	if stored.ID == 1 {
+		t.Errorf("existing recipe 1 was overwritten")
+	}
+	if stored.RatingAverage != 0 || stored.RatingCount != 0 {
+		t.Errorf("rating = %v/%d, want 0/0", stored.RatingAverage, stored.RatingCount)
+	}
+	if len(stored.Images) != 0 || len(stored.RecipeIngredients) != 0 || len(stored.Tags) != 0 {
+		t.Errorf("images = %d, ingredients = %d, tags = %d, want none", len(stored.Images), len(stored.RecipeIngredients), len(stored.Tags))
+	}
+}
+
+func TestAddRecipeHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"malformed body", `{"Title":`, http.StatusBadRequest},
+		{"unknown category", `{"Title":"Rendang","CategoryId":7}`, http.StatusBadRequest},
+		{"invalid ingredient line", `{"Title":"Rendang","CategoryId":1,"ingredients":[{"amount":"1"}]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, recipes := newRecipeTestHandler()
+			w := httptest.NewRecorder()
+			h.AddRecipeHandler(w, newRecipeRequest(http.MethodPost, "/api/recipe", tt.body, nil, testAuthor))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if len(recipes.recipes) != 1 {
+				t.Errorf("recipes = %d, want nothing created", len(recipes.recipes))
+			}
+		})
+	}
+}
+
+func TestUpdateRecipeHandler(t *testing.T) {
+	h, recipes := newRecipeTestHandler()
+
+	w := httptest.NewRecorder()
+	r := newRecipeRequest(http.MethodPut, "/api/recipe/1", `{"title":"Soto Betawi","servings":6}`, map[string]string{"id": "1"}, testAuthor)
+	h.UpdateRecipeHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var updated models.Recipe
+	decodeResponse(t, w, &updated)
+	if updated.Title != "Soto Betawi" || updated.Servings != 6 {
+		t.Errorf("updated = %q, %d servings", updated.Title, updated.Servings)
+	}
+	if len(recipes.updates) != 1 {
+		t.Fatalf("Update called %d times, want 1", len(recipes.updates))
+	}
+	update := recipes.updates[0]
+	if update.Descriptions != nil || update.Instructions != nil || update.CategoryId != nil {
+		t.Errorf("update touches fields that were not sent: %+v", update)
+	}
+	if update.EditorId == nil || *update.EditorId != testAuthor.UserId {
+		t.Errorf("EditorId = %v, want %d", update.EditorId, testAuthor.UserId)
+	}
+}
+
+func TestUpdateRecipeHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		body      string
+		principal *auth.Principal
+		want      int
+	}{
+		{"invalid id", "abc", `{"title":"x"}`, testAuthor, http.StatusBadRequest},
+		{"not found", "42", `{"title":"x"}`, testAuthor, http.StatusNotFound},
+		{"not the author", "1", `{"title":"x"}`, testOther, http.StatusForbidden},
+		{"no fields", "1", `{}`, testAuthor, http.StatusBadRequest},
+		{"unknown category", "1", `{"category_id":7}`, testAuthor, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, recipes := newRecipeTestHandler()
+			w := httptest.NewRecorder()
+			r := newRecipeRequest(http.MethodPut, "/api/recipe/"+tt.id, tt.body, map[string]string{"id": tt.id}, tt.principal)
+			h.UpdateRecipeHandler(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if len(recipes.updates) != 0 {
+				t.Errorf("Update called %d times, want 0", len(recipes.updates))
+			}
+		})
+	}
+}
+
+func TestUpdateRecipeHandlerModerator(t *testing.T) {
+	h, _ := newRecipeTestHandler()
+	w := httptest.NewRecorder()
+	r := newRecipeRequest(http.MethodPut, "/api/recipe/1", `{"title":"Moderated"}`, map[string]string{"id": "1"}, testAdmin)
+	h.UpdateRecipeHandler(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+}
+
+func TestRecipeHandlerNotFound(t *testing.T) {
+	h, _ := newRecipeTestHandler()
+	vars := map[string]string{"id": "42"}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+	}{
+		{"read", h.ReadbyIDHandler, http.MethodGet},
+		{"update", h.UpdateRecipeHandler, http.MethodPut},
+		{"delete", h.DeleteRecipeHandler, http.MethodDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, newRecipeRequest(tt.method, "/api/recipe/42", `{"title":"x"}`, vars, testAdmin))
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body)
+			}
+			response := decodeResponse(t, w, nil)
+			if response.Status != "error" {
+				t.Errorf("status field = %q, want error", response.Status)
+			}
+		})
+	}
+}
+
+func TestReadbyIDHandler(t *testing.T) {
+	h, _ := newRecipeTestHandler()
+	w := httptest.NewRecorder()
+	h.ReadbyIDHandler(w, newRecipeRequest(http.MethodGet, "/api/recipe/1", "", map[string]string{"id": "1"}, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var detail RecipeDetail
+	decodeResponse(t, w, &detail)
+	if detail.ID != 1 || detail.Title != "Soto Ayam" {
+		t.Errorf("detail = %d %q", detail.ID, detail.Title)
+	}
+}
+
+func TestScaleRecipeHandler(t *testing.T) {
+	h, _ := newRecipeTestHandler()
+	w := httptest.NewRecorder()
+	h.ScaleRecipeHandler(w, newRecipeRequest(http.MethodGet, "/api/recipe/1/scale?servings=6", "", map[string]string{"id": "1"}, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var scaled ScaledRecipe
+	decodeResponse(t, w, &scaled)
+	if scaled.Servings != 6 || scaled.OriginalServings != 4 || scaled.Factor != 1.5 {
+		t.Errorf("scaled = %d servings from %d, factor %g", scaled.Servings, scaled.OriginalServings, scaled.Factor)
+	}
+}
+
+func TestScaleRecipeHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"neither", ""},
+		{"both", "servings=2&factor=2"},
+		{"zero factor", "factor=0"},
+		{"negative factor", "factor=-1"},
+		{"NaN factor", "factor=NaN"},
+		{"infinite factor", "factor=Inf"},
+		{"huge factor", "factor=1e308"},
+		{"factor above limit", "factor=1001"},
+		{"zero servings", "servings=0"},
+		{"servings above limit", "servings=10001"},
+		{"huge servings", "servings=9223372036854775807"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newRecipeTestHandler()
+			w := httptest.NewRecorder()
+			h.ScaleRecipeHandler(w, newRecipeRequest(http.MethodGet, "/api/recipe/1/scale?"+tt.query, "", map[string]string{"id": "1"}, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+			if response := decodeResponse(t, w, nil); response.Status != "error" {
+				t.Errorf("status field = %q, want error", response.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-rest-modul/models"
+	"go-rest-modul/schemaorg"
+	"html/template"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Format response recipe selain JSON biasa.
+const (
+	formatJSON   = "json"
+	formatJSONLD = "jsonld"
+	formatHTML   = "html"
+)
+
+// recipeFormat menentukan format response dari parameter format, atau dari
+// header Accept jika parameter kosong. ok bernilai false untuk format yang
+// tidak dikenal.
+func recipeFormat(r *http.Request) (format string, ok bool) {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "":
+	case "json":
+		return formatJSON, true
+	case "jsonld", "json-ld", "ld+json", "schema":
+		return formatJSONLD, true
+	case "html":
+		return formatHTML, true
+	default:
+		return "", false
+	}
+
+	// Media type pertama yang dikenal pada Accept yang dipakai
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "application/json", "*/*", "application/*":
+			return formatJSON, true
+		case "application/ld+json":
+			return formatJSONLD, true
+		case "text/html":
+			return formatHTML, true
+		}
+	}
+	return formatJSON, true
+}
+
+// recipeDocument memetakan recipe ke schema.org/Recipe JSON-LD.
+func recipeDocument(recipe models.Recipe) schemaorg.Document {
+	source := schemaorg.Recipe{
+		Name:         recipe.Title,
+		Description:  recipe.Descriptions,
+		Instructions: schemaorg.SplitInstructions(recipe.Instructions),
+		PrepTime:     recipe.PrepTime,
+		CookTime:     recipe.CookTime,
+		Yield:        recipe.Servings,
+		Image:        recipe.ImageURL,
+		Category:     recipe.Category.Name,
+	}
+	for _, line := range recipe.RecipeIngredients {
+		text := strings.Join(strings.Fields(line.Amount+" "+line.Unit+" "+line.Ingredient.Name), " ")
+		if text != "" {
+			source.Ingredients = append(source.Ingredients, text)
+		}
+	}
+
+	doc := source.Document()
+	if !recipe.CreatedAt.IsZero() {
+		doc.DatePublished = recipe.CreatedAt.Format(time.RFC3339)
+		doc.DateModified = recipe.UpdatedAt.Format(time.RFC3339)
+	}
+	return doc
+}
+
+// recipeHTML adalah potongan HTML yang bisa disisipkan ke halaman lain. Data
+// yang sama juga disertakan sebagai JSON-LD agar terbaca mesin pencari.
+var recipeHTML = template.Must(template.New("recipe").Parse(`<article class="recipe" itemscope itemtype="https://schema.org/Recipe">
+<script type="application/ld+json">{{.JSON}}</script>
+<h2 class="recipe-name">{{.Doc.Name}}</h2>
+{{- if .Doc.Image}}
+<img class="recipe-image" src="{{.Doc.Image}}" alt="{{.Doc.Name}}">
+{{- end}}
+{{- if .Doc.Description}}
+<p class="recipe-description">{{.Doc.Description}}</p>
+{{- end}}
+<ul class="recipe-meta">
+{{- if .Doc.RecipeCategory}}
+<li>Category: {{.Doc.RecipeCategory}}</li>
+{{- end}}
+{{- if .Recipe.PrepTime}}
+<li>Prep time: {{.Recipe.PrepTime}} min</li>
+{{- end}}
+{{- if .Recipe.CookTime}}
+<li>Cook time: {{.Recipe.CookTime}} min</li>
+{{- end}}
+{{- if .Recipe.Servings}}
+<li>Servings: {{.Recipe.Servings}}</li>
+{{- end}}
+</ul>
+<h3>Ingredients</h3>
+<ul class="recipe-ingredients">
+{{- range .Doc.RecipeIngredient}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+<h3>Instructions</h3>
+<ol class="recipe-instructions">
+{{- range .Doc.RecipeInstructions}}
+<li>{{.Text}}</li>
+{{- end}}
+</ol>
+</article>
+`))
+
+// writeRecipeDocument menulis recipe dalam format JSON-LD atau HTML.
+func writeRecipeDocument(w http.ResponseWriter, recipe models.Recipe, format string) {
+	doc := recipeDocument(recipe)
+
+	if format == formatJSONLD {
+		w.Header().Set("Content-Type", "application/ld+json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(doc)
+		return
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while encoding data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	recipeHTML.Execute(w, struct {
+		Doc    schemaorg.Document
+		Recipe models.Recipe
+		JSON   template.JS
+	}{doc, recipe, template.JS(data)})
+}
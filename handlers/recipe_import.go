@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"go-rest-modul/schemaorg"
+	"go-rest-modul/units"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxImportSize membatasi ukuran dokumen JSON-LD/HTML yang diimpor.
+const maxImportSize = 5 << 20
+
+// RecipeImportPreview adalah hasil pemetaan JSON-LD sebelum disimpan. Field
+// recipe dan ingredients memakai bentuk yang sama dengan payload
+// AddRecipeHandler, jadi preview yang sudah diperbaiki bisa langsung dikirim
+// ke POST /api/recipe.
+type RecipeImportPreview struct {
+	models.Recipe
+	Ingredients []repository.IngredientLine `json:"ingredients"`
+	Warnings    []string                    `json:"warnings"`
+}
+
+// ImportRecipeHandler menerima dokumen schema.org/Recipe JSON-LD atau HTML
+// yang memuatnya. Secara default hanya mengembalikan preview; dengan
+// commit=true recipe disimpan dan category dibuat jika belum ada.
+func (h *RecipeHandler) ImportRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	params := r.URL.Query()
+
+	commit := false
+	if value := params.Get("commit"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "commit must be true or false",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		commit = parsed
+	}
+
+	var categoryId uint
+	if value := params.Get("category_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || parsed == 0 {
+			writeInvalidID(w)
+			return
+		}
+		categoryId = uint(parsed)
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while reading data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	recipes, err := schemaorg.Parse(body)
+	if err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	preview := previewImport(recipes[0])
+	if len(recipes) > 1 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("document contains %d recipes, only the first one is imported", len(recipes)))
+	}
+
+	// Category dari parameter category_id menggantikan recipeCategory
+	if categoryId != 0 {
+		category, err := h.categories.FindByID(r.Context(), categoryId)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "Category Not Found",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		preview.CategoryId = category.ID
+		preview.Category = category
+	} else if preview.Category.Name != "" {
+		category, err := h.categories.FindByName(r.Context(), preview.Category.Name)
+		switch {
+		case err == nil:
+			preview.CategoryId = category.ID
+			preview.Category = category
+		case errors.Is(err, repository.ErrNotFound):
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("category %q does not exist and will be created", preview.Category.Name))
+		default:
+			response := Response{
+				Status:  "error",
+				Message: "Error validating category: " + err.Error(),
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	} else {
+		preview.Warnings = append(preview.Warnings, "recipeCategory is missing, pass category_id to commit")
+	}
+
+	if !commit {
+		response := Response{
+			Status:  "success",
+			Message: "Recipe Import Preview",
+			Data:    preview,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if preview.CategoryId == 0 {
+		if preview.Category.Name == "" {
+			response := Response{
+				Status:  "error",
+				Message: "Category Not Found",
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		category := models.Category{Name: preview.Category.Name}
+		if err := h.categories.Create(r.Context(), &category); err != nil {
+			response := Response{
+				Status:  "error",
+				Message: "Failed to create category: " + err.Error(),
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		preview.CategoryId = category.ID
+	}
+
+	recipe := preview.Recipe
+	recipe.Category = models.Category{}
+	principal, _ := auth.FromContext(r.Context())
+	recipe.AuthorId = &principal.UserId
+	if err := h.recipes.Create(r.Context(), &recipe, preview.Ingredients, nil); err != nil {
+		if errors.Is(err, repository.ErrInvalidIngredientLine) {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while creating data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Receipt Imported Successfully",
+		Data:    recipe,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// previewImport memetakan schemaorg.Recipe ke Recipe dan baris bahan.
+func previewImport(source schemaorg.Recipe) RecipeImportPreview {
+	preview := RecipeImportPreview{
+		Recipe: models.Recipe{
+			Title:        source.Name,
+			Descriptions: source.Description,
+			PrepTime:     source.PrepTime,
+			CookTime:     source.CookTime,
+			Servings:     source.Yield,
+			ImageURL:     source.Image,
+			Category:     models.Category{Name: source.Category},
+		},
+		Ingredients: []repository.IngredientLine{},
+		Warnings:    []string{},
+	}
+
+	steps := make([]string, len(source.Instructions))
+	for i, step := range source.Instructions {
+		steps[i] = fmt.Sprintf("%d. %s", i+1, step)
+	}
+	preview.Instructions = strings.Join(steps, "\n")
+
+	if preview.Title == "" {
+		preview.Warnings = append(preview.Warnings, "recipe has no name")
+	}
+	if preview.Servings == 0 {
+		preview.Warnings = append(preview.Warnings, "recipeYield has no number of servings")
+	}
+
+	for i, text := range source.Ingredients {
+		amount, unit, name := units.SplitLine(text)
+		if name == "" {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("ingredient %d %q has no name and is skipped", i+1, text))
+			continue
+		}
+		if amount == "" {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("ingredient %d %q has no amount", i+1, text))
+		}
+		preview.Ingredients = append(preview.Ingredients, repository.IngredientLine{
+			Name:   name,
+			Amount: amount,
+			Unit:   unit,
+		})
+	}
+	return preview
+}
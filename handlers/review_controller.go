@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxReviewLength membatasi panjang teks review (dalam karakter).
+const maxReviewLength = 5000
+
+// ReviewInput adalah body untuk membuat atau mengubah review. Pada update,
+// field yang tidak dikirim tidak diubah.
+type ReviewInput struct {
+	Rating *int    `json:"rating"`
+	Body   *string `json:"body"`
+}
+
+type ReviewHandler struct {
+	reviews repository.ReviewRepository
+}
+
+func NewReviewHandler(reviews repository.ReviewRepository) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews}
+}
+
+// parseRatingFilter membaca parameter min_rating, max_rating dan min_reviews.
+func parseRatingFilter(r *http.Request) (repository.RatingFilter, error) {
+	params := r.URL.Query()
+	var filter repository.RatingFilter
+
+	parseRating := func(key string) (*float64, error) {
+		value := params.Get(key)
+		if value == "" {
+			return nil, nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		// Ditulis sebagai !(n >= 1 && n <= 5) agar NaN ikut ditolak
+		if err != nil || !(n >= 1 && n <= 5) {
+			return nil, errors.New(key + " must be a number between 1 and 5")
+		}
+		return &n, nil
+	}
+
+	var err error
+	if filter.MinRating, err = parseRating("min_rating"); err != nil {
+		return filter, err
+	}
+	if filter.MaxRating, err = parseRating("max_rating"); err != nil {
+		return filter, err
+	}
+	if filter.MinRating != nil && filter.MaxRating != nil && *filter.MinRating > *filter.MaxRating {
+		return filter, errors.New("min_rating cannot be greater than max_rating")
+	}
+	if value := params.Get("min_reviews"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return filter, errors.New("min_reviews must be a non-negative number")
+		}
+		filter.MinReviews = &n
+	}
+	return filter, nil
+}
+
+// validate memeriksa input review. Pada create rating wajib diisi.
+func (input *ReviewInput) validate(create bool) error {
+	if input.Rating == nil {
+		if create {
+			return errors.New("rating is required")
+		}
+	} else if *input.Rating < 1 || *input.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+	if input.Body != nil {
+		body := strings.TrimSpace(*input.Body)
+		if utf8.RuneCountInString(body) > maxReviewLength {
+			return errors.New("body must be at most " + strconv.Itoa(maxReviewLength) + " characters")
+		}
+		input.Body = &body
+	}
+	return nil
+}
+
+func writeReviewNotFound(w http.ResponseWriter) {
+	response := Response{
+		Status:  "not found",
+		Message: "Review Not Found",
+	}
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(response)
+}
+
+// decodeReviewInput membaca dan memvalidasi body request. Jika gagal,
+// response error sudah ditulis dan ok bernilai false.
+func decodeReviewInput(w http.ResponseWriter, r *http.Request, create bool) (input ReviewInput, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return input, false
+	}
+	if err := input.validate(create); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return input, false
+	}
+	return input, true
+}
+
+func (h *ReviewHandler) GetRecipeReviews(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	reviews, total, err := h.reviews.ListByRecipe(r.Context(), recipeId, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "success",
+			Message: "No Reviews Yet",
+			Data:    reviews,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(reviews) > 0 {
+		lastID = reviews[len(reviews)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Reviews Retrieved Successfully",
+		Data:       reviews,
+		Pagination: newPagination(r, opts, total, len(reviews), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddReview membuat review untuk recipe. Setiap user hanya boleh punya satu
+// review per recipe; review berikutnya harus lewat UpdateReview.
+func (h *ReviewHandler) AddReview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	input, ok := decodeReviewInput(w, r, true)
+	if !ok {
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	review := models.Review{
+		RecipeId: recipeId,
+		UserId:   principal.UserId,
+		Rating:   *input.Rating,
+	}
+	if input.Body != nil {
+		review.Body = *input.Body
+	}
+
+	if err := h.reviews.Create(r.Context(), &review); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			response := Response{
+				Status:  "not found",
+				Message: "Recipe Not Found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+		case errors.Is(err, repository.ErrReviewExists):
+			response := Response{
+				Status:  "error",
+				Message: "You have already reviewed this recipe",
+			}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(response)
+		default:
+			response := Response{
+				Status:  "error",
+				Message: "Error occurred while inserting data: " + err.Error(),
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(response)
+		}
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Review Added Successfully",
+		Data:    repository.ReviewResult{Review: review, Username: principal.Username},
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// findOwnReview mengambil review dan memastikan user yang login boleh
+// mengubahnya. Jika tidak, response error sudah ditulis dan ok bernilai false.
+func (h *ReviewHandler) findOwnReview(w http.ResponseWriter, r *http.Request, allowModerator bool) (review repository.ReviewResult, ok bool) {
+	reviewId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return review, false
+	}
+
+	review, err = h.reviews.FindByID(r.Context(), reviewId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeReviewNotFound(w)
+			return review, false
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Database error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return review, false
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if !principal.Owns(&review.UserId) && !(allowModerator && principal.Can(auth.PermRecipeModerate)) {
+		writeForbidden(w, "You can only change your own review")
+		return review, false
+	}
+	return review, true
+}
+
+// UpdateReview mengubah rating dan/atau teks review milik user sendiri.
+func (h *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	review, ok := h.findOwnReview(w, r, false)
+	if !ok {
+		return
+	}
+
+	input, ok := decodeReviewInput(w, r, false)
+	if !ok {
+		return
+	}
+
+	updated, err := h.reviews.Update(r.Context(), review.ID, repository.ReviewUpdate{Rating: input.Rating, Body: input.Body})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeReviewNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occurred while updating data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Review Updated Successfully",
+		Data:    updated,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteReview menghapus review milik user sendiri. Moderator boleh
+// menghapus review siapa saja.
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	review, ok := h.findOwnReview(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := h.reviews.Delete(r.Context(), review.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeReviewNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while deleting data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Review Deleted Successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRatingFilter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/recipe?min_rating=2.5&max_rating=5&min_reviews=3", nil)
+	filter, err := parseRatingFilter(r)
+	if err != nil {
+		t.Fatalf("parseRatingFilter: %v", err)
+	}
+	if filter.MinRating == nil || *filter.MinRating != 2.5 ||
+		filter.MaxRating == nil || *filter.MaxRating != 5 ||
+		filter.MinReviews == nil || *filter.MinReviews != 3 {
+		t.Errorf("filter = %+v", filter)
+	}
+
+	filter, err = parseRatingFilter(httptest.NewRequest(http.MethodGet, "/api/recipe", nil))
+	if err != nil || filter.MinRating != nil || filter.MaxRating != nil || filter.MinReviews != nil {
+		t.Errorf("empty query: filter = %+v, err = %v", filter, err)
+	}
+}
+
+func TestParseRatingFilterInvalid(t *testing.T) {
+	for _, query := range []string{
+		"min_rating=0",
+		"min_rating=6",
+		"min_rating=abc",
+		"min_rating=NaN",
+		"max_rating=NaN",
+		"min_rating=Inf",
+		"max_rating=-Inf",
+		"min_rating=4&max_rating=2",
+		"min_reviews=-1",
+		"min_reviews=1.5",
+	} {
+		if filter, err := parseRatingFilter(httptest.NewRequest(http.MethodGet, "/api/recipe?"+query, nil)); err == nil {
+			t.Errorf("%s: filter = %+v, want an error", query, filter)
+		}
+	}
+}
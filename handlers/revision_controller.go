@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type RevisionHandler struct {
+	recipes   repository.RecipeRepository
+	revisions repository.RecipeRevisionRepository
+}
+
+func NewRevisionHandler(recipes repository.RecipeRepository, revisions repository.RecipeRevisionRepository) *RevisionHandler {
+	return &RevisionHandler{recipes: recipes, revisions: revisions}
+}
+
+// parseRevisionNumber membaca nomor revisi. Jika allowCurrent bernilai true,
+// "current" berarti isi recipe saat ini (repository.CurrentRevision).
+func parseRevisionNumber(value string, allowCurrent bool) (int, error) {
+	if allowCurrent && value == "current" {
+		return repository.CurrentRevision, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		if allowCurrent {
+			return 0, errors.New("revision must be a positive number or current")
+		}
+		return 0, errors.New("revision must be a positive number")
+	}
+	return number, nil
+}
+
+func writeRevisionNotFound(w http.ResponseWriter) {
+	response := Response{
+		Status:  "not found",
+		Message: "Revision Not Found",
+	}
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeInvalidRevisionParam(w http.ResponseWriter, key string, err error) {
+	response := Response{
+		Status:  "error",
+		Message: key + ": " + err.Error(),
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+// findRecipe mencari recipe dari path variable id. Response error sudah
+// ditulis jika ok bernilai false.
+func (h *RevisionHandler) findRecipe(w http.ResponseWriter, r *http.Request) (recipe models.Recipe, ok bool) {
+	recipeId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return recipe, false
+	}
+
+	recipe, err = h.recipes.FindByID(r.Context(), recipeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "error",
+				Message: "Recipe Not Found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return recipe, false
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Database error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return recipe, false
+	}
+	return recipe, true
+}
+
+func (h *RevisionHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	revisions, total, err := h.revisions.List(r.Context(), recipe.ID, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "success",
+			Message: "No Revisions Yet",
+			Data:    revisions,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(revisions) > 0 {
+		lastID = revisions[len(revisions)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Revisions Retrieved Successfully",
+		Data:       revisions,
+		Pagination: newPagination(r, opts, total, len(revisions), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RevisionHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	number, err := parseRevisionNumber(mux.Vars(r)["number"], false)
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	revision, err := h.revisions.Find(r.Context(), recipe.ID, number)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeRevisionNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Revision Retrieved Successfully",
+		Data:    revision,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DiffRevisions membandingkan dua versi recipe lewat parameter from dan to.
+// Keduanya berisi nomor revisi atau "current"; to default ke "current".
+func (h *RevisionHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	from, err := parseRevisionNumber(params.Get("from"), true)
+	if err != nil {
+		writeInvalidRevisionParam(w, "from", err)
+		return
+	}
+	to := repository.CurrentRevision
+	if value := params.Get("to"); value != "" {
+		if to, err = parseRevisionNumber(value, true); err != nil {
+			writeInvalidRevisionParam(w, "to", err)
+			return
+		}
+	}
+
+	diff, err := h.revisions.Diff(r.Context(), recipe.ID, from, to)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeRevisionNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Revision Diff Retrieved Successfully",
+		Data:    diff,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RestoreRevision mengembalikan isi recipe ke revisi lama. Isi sebelum
+// restore tersimpan sebagai revisi baru sehingga restore bisa dibatalkan.
+func (h *RevisionHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	recipe, ok := h.findRecipe(w, r)
+	if !ok {
+		return
+	}
+	if !canModifyRecipe(r, recipe) {
+		writeForbidden(w, "Only the author or an admin can modify this recipe")
+		return
+	}
+
+	number, err := parseRevisionNumber(mux.Vars(r)["number"], false)
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	restored, err := h.revisions.Restore(r.Context(), recipe.ID, number, &principal.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeRevisionNotFound(w)
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidIngredientLine) || errors.Is(err, repository.ErrInvalidTag) ||
+			errors.Is(err, repository.ErrRevisionCategoryMissing) {
+			response := Response{
+				Status:  "error",
+				Message: "Revision cannot be restored: " + err.Error(),
+			}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occurred while restoring revision: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Revision Restored Successfully",
+		Data:    restored,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"go-rest-modul/units"
+	"net/http"
+	"strings"
+)
+
+type ShoppingListHandler struct {
+	lists repository.ShoppingListRepository
+}
+
+func NewShoppingListHandler(lists repository.ShoppingListRepository) *ShoppingListHandler {
+	return &ShoppingListHandler{lists: lists}
+}
+
+// wantsText bernilai true jika client meminta format teks lewat parameter
+// format=text atau header Accept: text/plain.
+func wantsText(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "text" || format == "txt"
+	}
+	return strings.HasPrefix(r.Header.Get("Accept"), "text/plain")
+}
+
+// writeShoppingListText menulis daftar belanja sebagai checklist teks biasa.
+func writeShoppingListText(w http.ResponseWriter, list models.ShoppingList) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintln(w, list.Name)
+	fmt.Fprintln(w)
+	for _, item := range list.Items {
+		mark := " "
+		if item.Checked {
+			mark = "x"
+		}
+		quantity := strings.TrimSpace(item.Amount + " " + item.Unit)
+		if quantity == "" {
+			fmt.Fprintf(w, "[%s] %s\n", mark, item.Ingredient.Name)
+			continue
+		}
+		fmt.Fprintf(w, "[%s] %s %s\n", mark, quantity, item.Ingredient.Name)
+	}
+}
+
+func (h *ShoppingListHandler) GetAllShoppingList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	lists, total, err := h.lists.List(r.Context(), principal.UserId, opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "Shopping list not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(lists) > 0 {
+		lastID = lists[len(lists)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Shopping list retrieved successfully",
+		Data:       lists,
+		Pagination: newPagination(r, opts, total, len(lists), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ShoppingListHandler) GetShoppingListbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	listId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	list, err := h.lists.FindByID(r.Context(), principal.UserId, listId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Shopping list not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if wantsText(r) {
+		writeShoppingListText(w, list)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Shopping list retrieved successfully",
+		Data:    list,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ShoppingListHandler) CreateShoppingList(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name    string                       `json:"name"`
+		Units   string                       `json:"units"`
+		Recipes []repository.RecipeSelection `json:"recipes"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if len(input.Recipes) == 0 {
+		response := Response{
+			Status:  "error",
+			Message: "Recipes cannot be empty",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var system units.System
+	if input.Units != "" {
+		parsed, err := units.ParseSystem(input.Units)
+		if err != nil {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		system = parsed
+	}
+
+	// Nama default supaya daftar tetap mudah dikenali
+	if input.Name == "" {
+		input.Name = "Shopping list"
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	list, err := h.lists.Create(r.Context(), repository.NewShoppingList{
+		UserId:  principal.UserId,
+		Name:    input.Name,
+		Recipes: input.Recipes,
+		System:  system,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidRecipeSelection) {
+			response := Response{
+				Status:  "error",
+				Message: err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Failed to create shopping list: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Shopping list created successfully",
+		Data:    list,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ShoppingListHandler) CheckShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Checked *bool `json:"checked"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	listId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+	itemId, err := parseID(r, "item_id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if input.Checked == nil {
+		response := Response{
+			Status:  "error",
+			Message: "checked is required",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	item, err := h.lists.SetItemChecked(r.Context(), principal.UserId, listId, itemId, *input.Checked)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Shopping list item not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Failed to update item: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Shopping list item updated successfully",
+		Data:    item,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ShoppingListHandler) DeleteShoppingList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	listId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.lists.Delete(r.Context(), principal.UserId, listId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response := Response{
+				Status:  "not found",
+				Message: "Shopping list not found",
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Failed to delete shopping list: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Shopping list deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+)
+
+type TagHandler struct {
+	tags repository.TagRepository
+}
+
+func NewTagHandler(tags repository.TagRepository) *TagHandler {
+	return &TagHandler{tags: tags}
+}
+
+// writeTagError menangani error repository yang umum pada endpoint tag.
+func writeTagError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response := Response{
+			Status:  "not found",
+			Message: "Tag not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+	case errors.Is(err, repository.ErrInvalidTag):
+		response := Response{
+			Status:  "error",
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+	case errors.Is(err, repository.ErrTagExists):
+		response := Response{
+			Status:  "error",
+			Message: "Tag already exists",
+		}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+	default:
+		response := Response{
+			Status:  "error",
+			Message: "error occured: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (h *TagHandler) GetAllTag(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	tags, total, err := h.tags.List(r.Context(), opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		response := Response{
+			Status:  "not found",
+			Message: "Tag not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var lastID uint
+	if len(tags) > 0 {
+		lastID = tags[len(tags)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Tag retrieved successfully",
+		Data:       tags,
+		Pagination: newPagination(r, opts, total, len(tags), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TagHandler) GetTagbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tagId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	tag, err := h.tags.FindByID(r.Context(), tagId)
+	if err != nil {
+		writeTagError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Tag retrieved successfully",
+		Data:    tag,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	tag := models.Tag{Name: input.Name}
+	if err := h.tags.Create(r.Context(), &tag); err != nil {
+		writeTagError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Tag created successfully",
+		Data:    tag,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateTag mengganti nama tag. Semua recipe yang memakai tag ini ikut
+// menampilkan nama baru.
+func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	tagId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	tag, err := h.tags.Rename(r.Context(), tagId, input.Name)
+	if err != nil {
+		writeTagError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Tag has been updated",
+		Data:    tag,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteTag menghapus tag dan melepasnya dari semua recipe.
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tagId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	if err := h.tags.Delete(r.Context(), tagId); err != nil {
+		writeTagError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "Tag has been deleted successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"go-rest-modul/models"
+	"go-rest-modul/units"
+	"math"
+	"net/http"
+)
+
+// parseUnitSystem membaca parameter units (metric atau us). ok bernilai
+// false jika parameter tidak diisi, artinya amount ditampilkan apa adanya.
+func parseUnitSystem(r *http.Request) (system units.System, ok bool, err error) {
+	value := r.URL.Query().Get("units")
+	if value == "" {
+		return "", false, nil
+	}
+	system, err = units.ParseSystem(value)
+	return system, err == nil, err
+}
+
+// localizeRecipe menulis ulang amount dan unit setiap baris bahan ke sistem
+// satuan yang diminta. Baris dengan amount atau unit yang tidak dikenali, serta
+// satuan hitungan, dibiarkan apa adanya.
+func localizeRecipe(recipe *models.Recipe, system units.System) {
+	for i := range recipe.RecipeIngredients {
+		line := &recipe.RecipeIngredients[i]
+		quantity, err := units.Parse(line.Amount, line.Unit)
+		if err != nil || quantity.Unit.System == units.Neutral {
+			continue
+		}
+		line.Amount, line.Unit = quantity.InSystem(system).Format()
+	}
+}
+
+func localizeRecipes(recipes []models.Recipe, system units.System) {
+	for i := range recipes {
+		localizeRecipe(&recipes[i], system)
+	}
+}
+
+const (
+	// maxScaleFactor dan maxScaledServings membatasi hasil scaling agar
+	// jumlah bahan dan servings tetap masuk akal dan tidak overflow.
+	maxScaleFactor    = 1000
+	maxScaledServings = 10000
+)
+
+// ScaledRecipe adalah recipe yang jumlah bahannya sudah dikalikan factor.
+type ScaledRecipe struct {
+	models.Recipe
+	Factor           float64 `json:"factor"`
+	OriginalServings int     `json:"original_servings"`
+}
+
+// scaleRecipe mengalikan setiap baris bahan dengan factor, membulatkan ke
+// pecahan dapur, dan menaikkan satuan bila perlu (48 tsp menjadi 1 cup). Jika
+// system diisi, hasilnya sekaligus ditampilkan pada sistem satuan itu. Baris
+// yang amount-nya tidak bisa dibaca dibiarkan apa adanya.
+func scaleRecipe(recipe models.Recipe, factor float64, system units.System) ScaledRecipe {
+	scaled := ScaledRecipe{
+		Recipe:           recipe,
+		Factor:           factor,
+		OriginalServings: recipe.Servings,
+	}
+	scaled.Servings = int(math.Round(float64(recipe.Servings) * factor))
+
+	// Salin slice agar data asli tidak ikut berubah
+	scaled.RecipeIngredients = append([]models.RecipeIngredient(nil), recipe.RecipeIngredients...)
+	for i := range scaled.RecipeIngredients {
+		line := &scaled.RecipeIngredients[i]
+		quantity, err := units.Parse(line.Amount, line.Unit)
+		if err != nil {
+			amount, err := units.ParseAmount(line.Amount)
+			if err != nil {
+				continue
+			}
+			// Satuan tidak dikenal (misal "siung"), tetap bisa dikalikan
+			line.Amount = units.FormatAmount(amount.Scale(factor), units.Neutral)
+			continue
+		}
+
+		quantity = quantity.Scale(factor)
+		switch {
+		case quantity.Unit.System == units.Neutral:
+		case system != "":
+			quantity = quantity.InSystem(system)
+		default:
+			quantity = quantity.Normalize()
+		}
+		amount, unit := quantity.Format()
+		line.Amount = amount
+		if quantity.Unit.System != units.Neutral {
+			line.Unit = unit
+		}
+	}
+	return scaled
+}
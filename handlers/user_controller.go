@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-rest-modul/auth"
+	"go-rest-modul/models"
+	"go-rest-modul/repository"
+	"net/http"
+	"strings"
+)
+
+// UserHandler berisi endpoint pengelolaan user. Pemeriksaan permission
+// dilakukan di routes, bukan di sini.
+type UserHandler struct {
+	users repository.UserRepository
+}
+
+func NewUserHandler(users repository.UserRepository) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+func writeUserNotFound(w http.ResponseWriter) {
+	response := Response{
+		Status:  "not found",
+		Message: "User Not Found",
+	}
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) GetAllUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeInvalidListOptions(w, err)
+		return
+	}
+
+	users, total, err := h.users.List(r.Context(), opts)
+	if err != nil {
+		if isListOptionsError(err) {
+			writeInvalidListOptions(w, err)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "error occured while retrieving data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if total == 0 {
+		writeUserNotFound(w)
+		return
+	}
+
+	var lastID uint
+	if len(users) > 0 {
+		lastID = users[len(users)-1].ID
+	}
+
+	response := Response{
+		Status:     "success",
+		Message:    "Users Retrieved Successfully",
+		Data:       users,
+		Pagination: newPagination(r, opts, total, len(users), lastID),
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) GetUserbyId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	user, err := h.users.FindByID(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUserNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Database error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "User Retrieved Successfully",
+		Data:    user,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateUserRole mengganti role user. Role baru berlaku saat user tersebut
+// me-refresh token. Admin tidak bisa mengubah role dirinya sendiri agar
+// tidak ada yang tanpa sengaja mengunci diri.
+func (h *UserHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	var input struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := Response{
+			Status:  "error",
+			Message: "Decode error: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if !auth.ValidRole(input.Role) {
+		response := Response{
+			Status:  "error",
+			Message: "role must be one of " + strings.Join(models.Roles, ", "),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if principal.UserId == userId {
+		writeForbidden(w, "You cannot change your own role")
+		return
+	}
+
+	user, err := h.users.UpdateRole(r.Context(), userId, input.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUserNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occurred while updating data: " + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "User Role Updated Successfully",
+		Data:    user,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId, err := parseID(r, "id")
+	if err != nil {
+		writeInvalidID(w)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if principal.UserId == userId {
+		writeForbidden(w, "You cannot delete your own account")
+		return
+	}
+
+	if err := h.users.Delete(r.Context(), userId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUserNotFound(w)
+			return
+		}
+		response := Response{
+			Status:  "error",
+			Message: "Error occured while deleting data :" + err.Error(),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := Response{
+		Status:  "success",
+		Message: "User Deleted Successfully",
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,157 @@
+// Package images memeriksa gambar recipe yang diunggah lalu membuat variant
+// yang lebih kecil (thumbnail) dalam JPEG atau PNG.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrUnsupportedFormat dikembalikan jika isi file bukan JPEG, PNG, GIF atau WebP.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooManyPixels dikembalikan jika lebar x tinggi gambar melebihi batas.
+	ErrTooManyPixels = errors.New("image dimensions too large")
+	// ErrInvalidImage dikembalikan jika file dikenali tetapi gagal di-decode.
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// extensions berisi format yang diterima beserta ekstensi file-nya. Format
+// dikenali dari isi file, bukan dari header Content-Type yang dikirim client.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Size adalah ukuran variant hasil resize. Gambar diperkecil sampai sisi
+// terpanjangnya tidak lebih dari MaxSide; gambar yang lebih kecil tidak
+// diperbesar.
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Sizes adalah variant yang dibuat untuk setiap gambar yang diunggah.
+var Sizes = []Size{
+	{Name: "medium", MaxSide: 1024},
+	{Name: "thumb", MaxSide: 320},
+}
+
+// VariantOriginal adalah nama variant untuk file asli yang diunggah.
+const VariantOriginal = "original"
+
+// Variant adalah satu file hasil pemrosesan gambar.
+type Variant struct {
+	Name        string
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// DetectType mengenali format gambar dari isi data.
+func DetectType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, contentType)
+	}
+	return contentType, nil
+}
+
+// Process memeriksa data lalu membuat semua variant: file asli apa adanya,
+// serta setiap Sizes dalam JPEG (PNG jika gambar transparan).
+// maxPixels membatasi lebar x tinggi sebelum gambar di-decode, sehingga file
+// kecil yang berisi gambar raksasa tidak menghabiskan memori.
+func Process(data []byte, maxPixels int) ([]Variant, error) {
+	contentType, err := DetectType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrInvalidImage)
+	}
+	if maxPixels > 0 && config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrTooManyPixels, config.Width, config.Height, maxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	variants := []Variant{{
+		Name:        VariantOriginal,
+		ContentType: contentType,
+		Ext:         extensions[contentType],
+		Width:       config.Width,
+		Height:      config.Height,
+		Data:        data,
+	}}
+	for _, size := range Sizes {
+		resized := resize(src, size.MaxSide)
+		bounds := resized.Bounds()
+
+		var buf bytes.Buffer
+		contentType, ext := "image/jpeg", ".jpg"
+		if resized.Opaque() {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+		} else {
+			// JPEG tidak punya alpha, jadi gambar transparan disimpan sebagai PNG
+			contentType, ext = "image/png", ".png"
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{
+			Name:        size.Name,
+			ContentType: contentType,
+			Ext:         ext,
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			Data:        buf.Bytes(),
+		})
+	}
+	return variants, nil
+}
+
+// resize memperkecil src sampai sisi terpanjangnya maxSide dengan menjaga
+// rasio. Hasilnya selalu dimulai dari titik (0, 0).
+func resize(src image.Image, maxSide int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			height = max(1, height*maxSide/width)
+			width = maxSide
+		} else {
+			width = max(1, width*maxSide/height)
+			height = maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,121 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage membuat gambar gradasi dengan pola kotak agar ada tepi tajam.
+// Jika alpha bernilai true, transparansi ikut bervariasi per piksel.
+func testImage(width, height int, alpha bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{
+				R: uint8(x * 255 / max(width-1, 1)),
+				G: uint8(y * 255 / max(height-1, 1)),
+				B: 128,
+				A: 255,
+			}
+			if (x/8+y/8)%2 == 0 {
+				c.B = 40
+			}
+			if alpha {
+				c.A = uint8((x + y) * 255 / max(width+height-2, 1))
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(1600, 900, false), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := Process(buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	want := []struct {
+		name, contentType string
+		width, height     int
+	}{
+		{VariantOriginal, "image/jpeg", 1600, 900},
+		{"medium", "image/jpeg", 1024, 576},
+		{"thumb", "image/jpeg", 320, 180},
+	}
+	if len(variants) != len(want) {
+		t.Fatalf("got %d variants, want %d", len(variants), len(want))
+	}
+	for i, v := range variants {
+		w := want[i]
+		if v.Name != w.name || v.ContentType != w.contentType || v.Width != w.width || v.Height != w.height {
+			t.Errorf("variant %d = %s %s %dx%d, want %s %s %dx%d",
+				i, v.Name, v.ContentType, v.Width, v.Height, w.name, w.contentType, w.width, w.height)
+			continue
+		}
+		config, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Errorf("variant %d does not decode: %v", i, err)
+			continue
+		}
+		if "image/"+format != v.ContentType || config.Width != v.Width || config.Height != v.Height {
+			t.Errorf("variant %d decodes as %s %dx%d", i, format, config.Width, config.Height)
+		}
+	}
+}
+
+func TestProcessTransparentKeepsAlpha(t *testing.T) {
+	variants, err := Process(encodePNG(t, testImage(200, 100, true)), 0)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(variants) != 1+len(Sizes) {
+		t.Fatalf("got %d variants, want %d", len(variants), 1+len(Sizes))
+	}
+	for _, v := range variants[1:] {
+		if v.ContentType != "image/png" {
+			t.Errorf("%s variant is %s, want png", v.Name, v.ContentType)
+		}
+		// Gambar lebih kecil dari ukuran variant tidak diperbesar
+		if v.Width != 200 || v.Height != 100 {
+			t.Errorf("%s variant is %dx%d, want 200x100", v.Name, v.Width, v.Height)
+		}
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	png := encodePNG(t, testImage(100, 100, false))
+	tests := []struct {
+		name      string
+		data      []byte
+		maxPixels int
+		want      error
+	}{
+		{"not an image", []byte("hello, world"), 0, ErrUnsupportedFormat},
+		{"truncated", png[:len(png)/2], 0, ErrInvalidImage},
+		{"too many pixels", png, 100*100 - 1, ErrTooManyPixels},
+	}
+	for _, tt := range tests {
+		if _, err := Process(tt.data, tt.maxPixels); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
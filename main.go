@@ -1,15 +1,101 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"go-rest-modul/auth"
+	"go-rest-modul/config"
+	"go-rest-modul/database"
+	"go-rest-modul/repository"
 	"go-rest-modul/routes"
+	"go-rest-modul/storage"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "path ke file konfigurasi YAML/TOML (opsional)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Konfigurasi tidak valid: ", err)
+	}
+
+	db, err := database.Connect(cfg.Database, cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.Auth.Secret == "" {
+		log.Println("auth.secret kosong, memakai kunci acak; semua token batal saat server dijalankan ulang")
+		cfg.Auth.Secret = auth.RandomSecret()
+	}
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal("Gagal menyiapkan storage: ", err)
+	}
+
 	log.Println("Memulai server")
 
-	routes := routes.RegisterRoutes()
+	deps := routes.Dependencies{
+		Recipes:        repository.NewRecipeRepository(db),
+		Categories:     repository.NewCategoryRepository(db),
+		Tags:           repository.NewTagRepository(db),
+		Ingredients:    repository.NewIngredientRepository(db),
+		ShoppingLists:  repository.NewShoppingListRepository(db),
+		MealPlans:      repository.NewMealPlanRepository(db),
+		Nutrients:      repository.NewNutrientRepository(db),
+		Catalog:        repository.NewCatalogRepository(db),
+		Users:          repository.NewUserRepository(db),
+		Reviews:        repository.NewReviewRepository(db),
+		Favorites:      repository.NewFavoriteRepository(db),
+		Collections:    repository.NewCollectionRepository(db),
+		Images:         repository.NewRecipeImageRepository(db),
+		Revisions:      repository.NewRecipeRevisionRepository(db),
+		Storage:        store,
+		Tokens:         auth.NewTokens(cfg.Auth),
+		PublicReads:    cfg.Auth.PublicReads,
+		DefaultRole:    cfg.Auth.DefaultRole,
+		MaxUploadSize:  cfg.Storage.MaxUploadSize,
+		MaxImagePixels: cfg.Storage.MaxPixels,
+	}
+	// Storage lokal dengan public_url berupa path dilayani server ini sendiri
+	if local, ok := store.(*storage.Local); ok && strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+		deps.Media = local.Handler()
+		deps.MediaPath = cfg.Storage.PublicURL
+	}
+	router := routes.RegisterRoutes(deps)
+
+	server := &http.Server{
+		Addr:         cfg.Server.Addr,
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// Tunggu sinyal berhenti lalu matikan server dengan rapi
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
 
-	log.Fatal(http.ListenAndServe(":8080", routes))
+	log.Println("Menghentikan server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Gagal menghentikan server: ", err)
+	}
 }
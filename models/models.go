@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -16,17 +18,35 @@ type Recipe struct {
 	CategoryId        uint
 	Category          Category           `gorm:"foreignKey:CategoryId"`
 	RecipeIngredients []RecipeIngredient `gorm:"foreignKey:RecipeId"`
+	Tags              []Tag              `gorm:"many2many:recipe_tags"`
+	// Images berisi file gambar yang diunggah beserta thumbnail-nya
+	Images []RecipeImage `gorm:"foreignKey:RecipeId"`
+	// AuthorId adalah user yang membuat recipe, nil untuk data lama
+	AuthorId *uint `gorm:"index"`
+	// Rata-rata dan jumlah review, dihitung ulang setiap review berubah
+	RatingAverage float64 `gorm:"not null;default:0;index"`
+	RatingCount   int     `gorm:"not null;default:0"`
 }
 type Ingredient struct {
 	gorm.Model
 	Name              string
+	Density           float64            // gram per mililiter, 0 jika tidak diketahui
 	RecipeIngredients []RecipeIngredient `gorm:"foreignKey:IngredientId"`
 }
 
 type Category struct {
 	gorm.Model
-	Name    string
-	Recipes []Recipe `gorm:"foreignKey:CategoryId"`
+	Name string
+	// ParentId adalah category induk, nil untuk category paling atas
+	ParentId *uint    `gorm:"index"`
+	Recipes  []Recipe `gorm:"foreignKey:CategoryId"`
+}
+
+// Tag adalah label lintas category seperti "vegan" atau "one-pot". Name
+// selalu disimpan dalam huruf kecil.
+type Tag struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex;not null"`
 }
 
 type RecipeIngredient struct {
@@ -38,3 +58,196 @@ type RecipeIngredient struct {
 	Amount       string
 	Unit         string
 }
+
+type ShoppingList struct {
+	gorm.Model
+	// UserId adalah pemilik daftar belanja
+	UserId  uint `gorm:"index"`
+	Name    string
+	Recipes []ShoppingListRecipe `gorm:"foreignKey:ShoppingListId"`
+	Items   []ShoppingListItem   `gorm:"foreignKey:ShoppingListId"`
+}
+
+// ShoppingListRecipe mencatat recipe yang dipakai untuk menyusun daftar
+// belanja beserta jumlah porsinya.
+type ShoppingListRecipe struct {
+	gorm.Model
+	ShoppingListId uint
+	RecipeId       uint
+	Recipe         Recipe `gorm:"foreignKey:RecipeId"`
+	Servings       int
+}
+
+type ShoppingListItem struct {
+	gorm.Model
+	ShoppingListId uint
+	IngredientId   uint
+	Ingredient     Ingredient `gorm:"foreignKey:IngredientId"`
+	Amount         string
+	Unit           string
+	Checked        bool
+}
+
+type MealPlan struct {
+	gorm.Model
+	// UserId adalah pemilik meal plan
+	UserId    uint `gorm:"index"`
+	Name      string
+	StartDate time.Time       `gorm:"type:date"`
+	Entries   []MealPlanEntry `gorm:"foreignKey:MealPlanId"`
+}
+
+// MealPlanEntry adalah satu recipe pada tanggal dan slot makan tertentu.
+// Servings 0 berarti memakai jumlah porsi recipe.
+type MealPlanEntry struct {
+	gorm.Model
+	MealPlanId uint      `gorm:"index"`
+	Date       time.Time `gorm:"type:date;index"`
+	Slot       string
+	RecipeId   uint
+	Recipe     Recipe `gorm:"foreignKey:RecipeId"`
+	Servings   int
+}
+
+// IngredientNutrient berisi kandungan gizi bahan per 100 gram. Satuan: kcal
+// untuk Calories, gram untuk makronutrien, dan miligram untuk mikronutrien.
+type IngredientNutrient struct {
+	gorm.Model
+	IngredientId uint `gorm:"uniqueIndex"`
+	// FdcId dan Description diisi jika data berasal dari USDA FoodData Central
+	FdcId        int
+	Description  string
+	Calories     float64
+	Protein      float64
+	Fat          float64
+	SaturatedFat float64
+	Carbohydrate float64
+	Fiber        float64
+	Sugar        float64
+	Cholesterol  float64
+	Sodium       float64
+	Calcium      float64
+	Iron         float64
+	Potassium    float64
+	VitaminC     float64
+}
+
+// Role user. Viewer hanya membaca, editor boleh menulis recipe, category dan
+// ingredient, admin boleh semuanya termasuk mengelola user. Daftar permission
+// tiap role ada di package auth.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Roles berisi semua role, dari yang paling terbatas.
+var Roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+type User struct {
+	gorm.Model
+	Username string `gorm:"uniqueIndex;not null"`
+	Email    string `gorm:"uniqueIndex;not null"`
+	Role     string `gorm:"not null;default:viewer"`
+	// PasswordHash berisi hash bcrypt dan tidak pernah ikut di response
+	PasswordHash string `json:"-"`
+}
+
+// Session adalah refresh token yang pernah diterbitkan. TokenId sama dengan
+// claim jti pada JWT refresh token; session yang dicabut tidak bisa dipakai
+// untuk refresh lagi.
+type Session struct {
+	gorm.Model
+	UserId    uint   `gorm:"index"`
+	TokenId   string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// Review adalah rating 1-5 dan ulasan dari satu user untuk satu recipe.
+type Review struct {
+	gorm.Model
+	RecipeId uint `gorm:"uniqueIndex:idx_reviews_recipe_user"`
+	UserId   uint `gorm:"uniqueIndex:idx_reviews_recipe_user"`
+	Rating   int
+	Body     string
+}
+
+// Favorite adalah recipe yang ditandai (bookmark) oleh user.
+type Favorite struct {
+	gorm.Model
+	UserId   uint   `gorm:"uniqueIndex:idx_favorites_user_recipe"`
+	RecipeId uint   `gorm:"uniqueIndex:idx_favorites_user_recipe;index"`
+	Recipe   Recipe `gorm:"foreignKey:RecipeId"`
+}
+
+// Collection adalah kumpulan recipe milik user dengan urutan tertentu. Jika
+// ShareToken diisi, collection bisa dibaca siapa saja yang punya link-nya.
+type Collection struct {
+	gorm.Model
+	UserId      uint `gorm:"index"`
+	Name        string
+	Description string
+	ShareToken  *string           `gorm:"uniqueIndex"`
+	Entries     []CollectionEntry `gorm:"foreignKey:CollectionId"`
+}
+
+// CollectionEntry adalah satu recipe pada collection. Entry diurutkan
+// berdasarkan Position.
+type CollectionEntry struct {
+	gorm.Model
+	CollectionId uint   `gorm:"uniqueIndex:idx_collection_entries_recipe"`
+	RecipeId     uint   `gorm:"uniqueIndex:idx_collection_entries_recipe;index"`
+	Recipe       Recipe `gorm:"foreignKey:RecipeId"`
+	Position     int
+}
+
+// RecipeImage adalah satu file gambar recipe di storage. Variant "original"
+// adalah file yang diunggah, variant lain adalah hasil resize seperti
+// "medium" dan "thumb" dalam JPEG atau PNG.
+type RecipeImage struct {
+	gorm.Model
+	RecipeId    uint `gorm:"index"`
+	Variant     string
+	ContentType string
+	// Key adalah path file di storage, dipakai saat gambar diganti atau dihapus
+	Key    string `json:"-"`
+	URL    string
+	Width  int
+	Height int
+	Size   int
+}
+
+// RecipeRevision adalah salinan isi recipe sebelum satu kali update. Number
+// berurutan per recipe mulai dari 1; isi recipe saat ini tidak disimpan
+// sebagai revisi.
+type RecipeRevision struct {
+	gorm.Model
+	RecipeId uint `gorm:"uniqueIndex:idx_recipe_revisions_number"`
+	Number   int  `gorm:"uniqueIndex:idx_recipe_revisions_number"`
+	// EditorId adalah user yang melakukan update, nil jika tidak diketahui
+	EditorId     *uint
+	Title        string
+	Descriptions string
+	Instructions string
+	PrepTime     int
+	CookTime     int
+	Servings     int
+	ImageURL     string
+	CategoryId   uint
+	// Tags berisi nama tag dipisah baris baru, karena nama tag boleh berisi koma
+	Tags        string
+	Ingredients []RecipeRevisionIngredient `gorm:"foreignKey:RevisionId"`
+}
+
+// RecipeRevisionIngredient adalah salinan satu baris bahan pada revisi. Nama
+// bahan ikut disalin agar revisi tetap terbaca jika bahannya diubah.
+type RecipeRevisionIngredient struct {
+	gorm.Model
+	RevisionId   uint `gorm:"index"`
+	Position     int
+	IngredientId uint
+	Name         string
+	Amount       string
+	Unit         string
+}
@@ -0,0 +1,94 @@
+package nutrition
+
+import (
+	"go-rest-modul/models"
+	"go-rest-modul/units"
+)
+
+// Alasan baris bahan tidak ikut dihitung.
+const (
+	ReasonInvalidAmount = "amount could not be parsed"
+	ReasonUnknownUnit   = "unknown unit"
+	ReasonNoDensity     = "volume unit but ingredient has no density"
+	ReasonCountUnit     = "count unit has no weight"
+	ReasonNoNutrients   = "ingredient has no nutrient data"
+)
+
+// Report adalah hasil perhitungan gizi sebuah recipe.
+type Report struct {
+	Total      Facts  `json:"total"`
+	PerServing *Facts `json:"per_serving,omitempty"`
+	Servings   int    `json:"servings"`
+	// Complete bernilai true jika semua baris bahan ikut dihitung
+	Complete    bool          `json:"complete"`
+	Unconverted []SkippedLine `json:"unconverted"`
+}
+
+// SkippedLine adalah baris bahan yang tidak bisa diubah ke gram atau tidak
+// punya data gizi, sehingga tidak ikut dalam total.
+type SkippedLine struct {
+	RecipeIngredientId uint   `json:"recipe_ingredient_id"`
+	Ingredient         string `json:"ingredient"`
+	Amount             string `json:"amount"`
+	Unit               string `json:"unit"`
+	Reason             string `json:"reason"`
+}
+
+// Calculate menjumlahkan gizi semua baris bahan recipe. table berisi data
+// gizi per 100 g dengan key IngredientId. Rentang seperti "2-3" dihitung
+// dengan nilai tengahnya.
+func Calculate(recipe models.Recipe, table map[uint]models.IngredientNutrient) Report {
+	report := Report{
+		Servings:    recipe.Servings,
+		Unconverted: []SkippedLine{},
+	}
+
+	for _, line := range recipe.RecipeIngredients {
+		skip := func(reason string) {
+			report.Unconverted = append(report.Unconverted, SkippedLine{
+				RecipeIngredientId: line.ID,
+				Ingredient:         line.Ingredient.Name,
+				Amount:             line.Amount,
+				Unit:               line.Unit,
+				Reason:             reason,
+			})
+		}
+
+		amount, err := units.ParseAmount(line.Amount)
+		if err != nil {
+			skip(ReasonInvalidAmount)
+			continue
+		}
+		unit, err := units.Lookup(line.Unit)
+		if err != nil {
+			skip(ReasonUnknownUnit)
+			continue
+		}
+		if unit.Dimension == units.Count {
+			skip(ReasonCountUnit)
+			continue
+		}
+
+		value := (amount.Min + amount.Max) / 2
+		grams, err := units.Convert(value, unit, units.Gram, line.Ingredient.Density)
+		if err != nil {
+			skip(ReasonNoDensity)
+			continue
+		}
+
+		nutrient, ok := table[line.IngredientId]
+		if !ok {
+			skip(ReasonNoNutrients)
+			continue
+		}
+		report.Total = report.Total.Add(FromModel(nutrient).Scale(grams / 100))
+	}
+
+	report.Complete = len(report.Unconverted) == 0
+	if recipe.Servings > 0 {
+		perServing := report.Total.Scale(1 / float64(recipe.Servings)).Round()
+		report.PerServing = &perServing
+	}
+	report.Total = report.Total.Round()
+	return report
+}
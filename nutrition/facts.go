@@ -0,0 +1,132 @@
+package nutrition
+
+import (
+	"go-rest-modul/models"
+	"math"
+)
+
+// Facts adalah kandungan gizi untuk jumlah tertentu: per 100 g pada tabel
+// bahan, atau total pada hasil perhitungan recipe.
+type Facts struct {
+	Calories     float64 `json:"calories_kcal"`
+	Protein      float64 `json:"protein_g"`
+	Fat          float64 `json:"fat_g"`
+	SaturatedFat float64 `json:"saturated_fat_g"`
+	Carbohydrate float64 `json:"carbohydrate_g"`
+	Fiber        float64 `json:"fiber_g"`
+	Sugar        float64 `json:"sugar_g"`
+	Cholesterol  float64 `json:"cholesterol_mg"`
+	Sodium       float64 `json:"sodium_mg"`
+	Calcium      float64 `json:"calcium_mg"`
+	Iron         float64 `json:"iron_mg"`
+	Potassium    float64 `json:"potassium_mg"`
+	VitaminC     float64 `json:"vitamin_c_mg"`
+}
+
+// FromModel mengambil kandungan gizi per 100 g dari tabel bahan.
+func FromModel(n models.IngredientNutrient) Facts {
+	return Facts{
+		Calories:     n.Calories,
+		Protein:      n.Protein,
+		Fat:          n.Fat,
+		SaturatedFat: n.SaturatedFat,
+		Carbohydrate: n.Carbohydrate,
+		Fiber:        n.Fiber,
+		Sugar:        n.Sugar,
+		Cholesterol:  n.Cholesterol,
+		Sodium:       n.Sodium,
+		Calcium:      n.Calcium,
+		Iron:         n.Iron,
+		Potassium:    n.Potassium,
+		VitaminC:     n.VitaminC,
+	}
+}
+
+// Apply menyalin kandungan gizi ke baris tabel bahan.
+func (f Facts) Apply(n *models.IngredientNutrient) {
+	n.Calories = f.Calories
+	n.Protein = f.Protein
+	n.Fat = f.Fat
+	n.SaturatedFat = f.SaturatedFat
+	n.Carbohydrate = f.Carbohydrate
+	n.Fiber = f.Fiber
+	n.Sugar = f.Sugar
+	n.Cholesterol = f.Cholesterol
+	n.Sodium = f.Sodium
+	n.Calcium = f.Calcium
+	n.Iron = f.Iron
+	n.Potassium = f.Potassium
+	n.VitaminC = f.VitaminC
+}
+
+// HasNegative bernilai true jika ada nilai yang negatif.
+func (f Facts) HasNegative() bool {
+	for _, v := range []float64{
+		f.Calories, f.Protein, f.Fat, f.SaturatedFat, f.Carbohydrate, f.Fiber, f.Sugar,
+		f.Cholesterol, f.Sodium, f.Calcium, f.Iron, f.Potassium, f.VitaminC,
+	} {
+		if v < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Facts) Add(g Facts) Facts {
+	return Facts{
+		Calories:     f.Calories + g.Calories,
+		Protein:      f.Protein + g.Protein,
+		Fat:          f.Fat + g.Fat,
+		SaturatedFat: f.SaturatedFat + g.SaturatedFat,
+		Carbohydrate: f.Carbohydrate + g.Carbohydrate,
+		Fiber:        f.Fiber + g.Fiber,
+		Sugar:        f.Sugar + g.Sugar,
+		Cholesterol:  f.Cholesterol + g.Cholesterol,
+		Sodium:       f.Sodium + g.Sodium,
+		Calcium:      f.Calcium + g.Calcium,
+		Iron:         f.Iron + g.Iron,
+		Potassium:    f.Potassium + g.Potassium,
+		VitaminC:     f.VitaminC + g.VitaminC,
+	}
+}
+
+func (f Facts) Scale(factor float64) Facts {
+	return Facts{
+		Calories:     f.Calories * factor,
+		Protein:      f.Protein * factor,
+		Fat:          f.Fat * factor,
+		SaturatedFat: f.SaturatedFat * factor,
+		Carbohydrate: f.Carbohydrate * factor,
+		Fiber:        f.Fiber * factor,
+		Sugar:        f.Sugar * factor,
+		Cholesterol:  f.Cholesterol * factor,
+		Sodium:       f.Sodium * factor,
+		Calcium:      f.Calcium * factor,
+		Iron:         f.Iron * factor,
+		Potassium:    f.Potassium * factor,
+		VitaminC:     f.VitaminC * factor,
+	}
+}
+
+// Round membulatkan setiap nilai ke satu angka di belakang koma agar
+// response tidak dipenuhi angka pecahan panjang.
+func (f Facts) Round() Facts {
+	round := func(v float64) float64 {
+		return math.Round(v*10) / 10
+	}
+	return Facts{
+		Calories:     round(f.Calories),
+		Protein:      round(f.Protein),
+		Fat:          round(f.Fat),
+		SaturatedFat: round(f.SaturatedFat),
+		Carbohydrate: round(f.Carbohydrate),
+		Fiber:        round(f.Fiber),
+		Sugar:        round(f.Sugar),
+		Cholesterol:  round(f.Cholesterol),
+		Sodium:       round(f.Sodium),
+		Calcium:      round(f.Calcium),
+		Iron:         round(f.Iron),
+		Potassium:    round(f.Potassium),
+		VitaminC:     round(f.VitaminC),
+	}
+}
@@ -0,0 +1,257 @@
+package nutrition
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Food adalah satu bahan makanan dari dump USDA FoodData Central beserta
+// kandungan gizinya per 100 g.
+type Food struct {
+	FdcId       int
+	DataType    string
+	Description string
+	Facts       Facts
+}
+
+// fdcNutrients memetakan nutrient_id FoodData Central ke field Facts. Energi
+// pada Foundation Foods kadang hanya tersedia sebagai Atwater (2047/2048),
+// jadi dipakai sebagai cadangan jika 1008 tidak ada.
+var fdcNutrients = map[int]func(*Facts) *float64{
+	1008: func(f *Facts) *float64 { return &f.Calories },
+	1003: func(f *Facts) *float64 { return &f.Protein },
+	1004: func(f *Facts) *float64 { return &f.Fat },
+	1258: func(f *Facts) *float64 { return &f.SaturatedFat },
+	1005: func(f *Facts) *float64 { return &f.Carbohydrate },
+	1079: func(f *Facts) *float64 { return &f.Fiber },
+	2000: func(f *Facts) *float64 { return &f.Sugar },
+	1253: func(f *Facts) *float64 { return &f.Cholesterol },
+	1093: func(f *Facts) *float64 { return &f.Sodium },
+	1087: func(f *Facts) *float64 { return &f.Calcium },
+	1089: func(f *Facts) *float64 { return &f.Iron },
+	1092: func(f *Facts) *float64 { return &f.Potassium },
+	1162: func(f *Facts) *float64 { return &f.VitaminC },
+}
+
+var fdcEnergyFallbacks = []int{2047, 2048}
+
+// dataTypeRank mengutamakan data hasil analisis dibanding produk bermerek.
+var dataTypeRank = map[string]int{
+	"foundation_food":   0,
+	"sr_legacy_food":    1,
+	"survey_fndds_food": 2,
+	"branded_food":      3,
+}
+
+// ImportFDC membaca food.csv dan food_nutrient.csv dari folder dump
+// FoodData Central, lalu mencari makanan yang paling cocok untuk setiap nama
+// bahan. Deskripsi harus sama persis dengan nama atau diawali "nama,",
+// misalnya "Salt, table" untuk "salt". Jenis data hasil analisis lebih
+// diutamakan daripada produk bermerek, lalu kecocokan persis, lalu deskripsi
+// yang lebih pendek. Hasilnya memakai key nama bahan; nama tanpa kecocokan
+// tidak ada di hasil.
+func ImportFDC(dir string, names []string) (map[string]Food, error) {
+	matches, err := matchFoods(filepath.Join(dir, "food.csv"), names)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return map[string]Food{}, nil
+	}
+
+	byId := make(map[int]*Food, len(matches))
+	for _, food := range matches {
+		byId[food.FdcId] = food
+	}
+	if err := readFoodNutrients(filepath.Join(dir, "food_nutrient.csv"), byId); err != nil {
+		return nil, err
+	}
+
+	foods := make(map[string]Food, len(matches))
+	for name, food := range matches {
+		foods[name] = *food
+	}
+	return foods, nil
+}
+
+// csvFile membuka file CSV dan mengembalikan posisi kolom berdasarkan header.
+func csvFile(path string, columns ...string) (*csv.Reader, []int, io.Closer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reader := csv.NewReader(file)
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	index := make([]int, len(columns))
+	for i, column := range columns {
+		index[i] = -1
+		for j, name := range header {
+			if strings.TrimPrefix(name, "\ufeff") == column {
+				index[i] = j
+			}
+		}
+		if index[i] < 0 {
+			file.Close()
+			return nil, nil, nil, fmt.Errorf("%s: missing column %q", path, column)
+		}
+	}
+	return reader, index, file, nil
+}
+
+func matchFoods(path string, names []string) (map[string]*Food, error) {
+	reader, col, file, err := csvFile(path, "fdc_id", "data_type", "description")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	wanted := make(map[string]string, len(names))
+	for _, name := range names {
+		if key := strings.ToLower(strings.TrimSpace(name)); key != "" {
+			wanted[key] = name
+		}
+	}
+
+	// score lebih kecil lebih baik: jenis data, kecocokan, panjang deskripsi
+	type candidate struct {
+		food  *Food
+		score [3]int
+	}
+	best := make(map[string]candidate)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		description := record[col[2]]
+		lower := strings.ToLower(description)
+		key, exact := lower, true
+		if _, ok := wanted[key]; !ok {
+			prefix, _, found := strings.Cut(lower, ",")
+			if !found {
+				continue
+			}
+			if _, ok := wanted[prefix]; !ok {
+				continue
+			}
+			key, exact = prefix, false
+		}
+
+		rank, ok := dataTypeRank[record[col[1]]]
+		if !ok {
+			rank = len(dataTypeRank)
+		}
+		score := [3]int{rank, 1, len(description)}
+		if exact {
+			score[1] = 0
+		}
+		if current, ok := best[key]; ok && !lessScore(score, current.score) {
+			continue
+		}
+
+		fdcId, err := strconv.Atoi(record[col[0]])
+		if err != nil {
+			continue
+		}
+		best[key] = candidate{
+			food:  &Food{FdcId: fdcId, DataType: record[col[1]], Description: description},
+			score: score,
+		}
+	}
+
+	matches := make(map[string]*Food, len(best))
+	for key, c := range best {
+		matches[wanted[key]] = c.food
+	}
+	return matches, nil
+}
+
+func lessScore(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// readFoodNutrients membaca food_nutrient.csv secara streaming dan hanya
+// menyimpan nilai untuk makanan yang ada di foods, karena file ini bisa
+// berukuran beberapa gigabyte.
+func readFoodNutrients(path string, foods map[int]*Food) error {
+	reader, col, file, err := csvFile(path, "fdc_id", "nutrient_id", "amount")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	energy := make(map[int]map[int]float64)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		fdcId, err := strconv.Atoi(record[col[0]])
+		if err != nil {
+			continue
+		}
+		food, ok := foods[fdcId]
+		if !ok {
+			continue
+		}
+		nutrientId, err1 := strconv.Atoi(record[col[1]])
+		amount, err2 := strconv.ParseFloat(record[col[2]], 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		if field, ok := fdcNutrients[nutrientId]; ok {
+			*field(&food.Facts) = amount
+			continue
+		}
+		for _, id := range fdcEnergyFallbacks {
+			if nutrientId == id {
+				if energy[fdcId] == nil {
+					energy[fdcId] = make(map[int]float64)
+				}
+				energy[fdcId][id] = amount
+			}
+		}
+	}
+
+	for fdcId, values := range energy {
+		food := foods[fdcId]
+		if food.Facts.Calories != 0 {
+			continue
+		}
+		for _, id := range fdcEnergyFallbacks {
+			if value, ok := values[id]; ok {
+				food.Facts.Calories = value
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-rest-modul/catalog"
+	"go-rest-modul/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// errRollback dipakai untuk membatalkan transaksi impor tanpa dianggap gagal.
+var errRollback = errors.New("rollback")
+
+type CatalogRepository interface {
+	// Import melakukan upsert berdasarkan natural key: nama category, nama
+	// ingredient dan judul recipe. Semua baris diproses dalam satu transaksi
+	// yang hanya disimpan jika tidak ada error dan dryRun bernilai false.
+	// Error per baris dilaporkan di Report; error yang dikembalikan hanya
+	// untuk kegagalan database. Recipe baru dicatat atas nama authorId;
+	// 0 berarti tanpa author. Isi recipe lama disimpan sebagai revisi atas
+	// nama authorId sebelum diubah pertama kali dalam satu impor.
+	Import(ctx context.Context, records []catalog.Record, authorId uint, dryRun bool) (catalog.Report, error)
+	// Export memanggil emit untuk setiap baris katalog secara bertahap:
+	// category, ingredient, lalu recipe yang masing-masing diikuti baris
+	// bahannya.
+	Export(ctx context.Context, emit func(catalog.Record) error) error
+}
+
+// exportBatchSize adalah jumlah data yang dibaca per query saat ekspor.
+const exportBatchSize = 200
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) CatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+func (r *catalogRepository) Import(ctx context.Context, records []catalog.Record, authorId uint, dryRun bool) (catalog.Report, error) {
+	report := catalog.Report{Rows: len(records), DryRun: dryRun, Errors: []catalog.RowError{}}
+	fail := func(record catalog.Record, format string, args ...interface{}) {
+		report.Errors = append(report.Errors, catalog.NewRowError(record, format, args...))
+	}
+
+	for _, record := range records {
+		switch record.Type {
+		case catalog.TypeCategory, catalog.TypeIngredient, catalog.TypeRecipe, catalog.TypeLine:
+		default:
+			fail(record, "unknown type %q", record.Type)
+		}
+	}
+
+	var editorId *uint
+	if authorId != 0 {
+		editorId = &authorId
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Satu recipe cukup satu revisi per impor, walaupun field dan
+		// baris bahannya sama-sama berubah. Recipe baru tidak butuh revisi.
+		revised := make(map[uint]bool)
+		revise := func(recipeId uint) error {
+			if revised[recipeId] {
+				return nil
+			}
+			revised[recipeId] = true
+			return createRevision(tx, recipeId, editorId)
+		}
+
+		// Urutan baris di file tidak penting: category dan ingredient lebih
+		// dulu, lalu recipe, lalu baris bahan
+		for _, record := range records {
+			if record.Type != catalog.TypeCategory {
+				continue
+			}
+			name := strings.TrimSpace(record.Category)
+			if name == "" {
+				fail(record, "category is required")
+				continue
+			}
+			var category models.Category
+			result := tx.Where(models.Category{Name: name}).Limit(1).Find(&category)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				category.Name = name
+				if err := tx.Omit("Recipes").Create(&category).Error; err != nil {
+					return err
+				}
+				report.Categories.Created++
+			}
+		}
+
+		for _, record := range records {
+			if record.Type != catalog.TypeIngredient {
+				continue
+			}
+			if err := importIngredient(tx, record, &report, fail); err != nil {
+				return err
+			}
+		}
+
+		for _, record := range records {
+			if record.Type != catalog.TypeRecipe {
+				continue
+			}
+			if err := importRecipe(tx, record, authorId, revised, revise, &report, fail); err != nil {
+				return err
+			}
+		}
+
+		if err := importLines(tx, records, revise, &report, fail); err != nil {
+			return err
+		}
+
+		if dryRun || len(report.Errors) > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return report, err
+	}
+	report.Committed = err == nil
+	return report, nil
+}
+
+func importIngredient(tx *gorm.DB, record catalog.Record, report *catalog.Report, fail func(catalog.Record, string, ...interface{})) error {
+	name := strings.TrimSpace(record.Ingredient)
+	if name == "" {
+		fail(record, "ingredient is required")
+		return nil
+	}
+	if record.Density != nil && *record.Density < 0 {
+		fail(record, "density cannot be negative")
+		return nil
+	}
+
+	var ingredient models.Ingredient
+	result := tx.Where(models.Ingredient{Name: name}).Limit(1).Find(&ingredient)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		ingredient.Name = name
+		if record.Density != nil {
+			ingredient.Density = *record.Density
+		}
+		if err := tx.Omit("RecipeIngredients").Create(&ingredient).Error; err != nil {
+			return err
+		}
+		report.Ingredients.Created++
+		return nil
+	}
+
+	if record.Density != nil && *record.Density != ingredient.Density {
+		if err := tx.Model(&ingredient).Update("density", *record.Density).Error; err != nil {
+			return err
+		}
+		report.Ingredients.Updated++
+	}
+	return nil
+}
+
+func importRecipe(tx *gorm.DB, record catalog.Record, authorId uint, revised map[uint]bool, revise func(uint) error, report *catalog.Report, fail func(catalog.Record, string, ...interface{})) error {
+	title := strings.TrimSpace(record.Recipe)
+	if title == "" {
+		fail(record, "recipe is required")
+		return nil
+	}
+	for _, value := range []*int{record.PrepTime, record.CookTime, record.Servings} {
+		if value != nil && *value < 0 {
+			fail(record, "prep_time, cook_time and servings cannot be negative")
+			return nil
+		}
+	}
+
+	var categoryId *uint
+	if name := strings.TrimSpace(record.Category); name != "" {
+		var category models.Category
+		result := tx.Where(models.Category{Name: name}).Limit(1).Find(&category)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			fail(record, "category %q not found", name)
+			return nil
+		}
+		categoryId = &category.ID
+	}
+
+	var recipe models.Recipe
+	result := tx.Where(models.Recipe{Title: title}).Order("id").Limit(1).Find(&recipe)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		// Hanya field yang diisi dan nilainya berbeda yang diubah, jadi
+		// mengimpor ulang hasil ekspor tidak dihitung sebagai perubahan
+		var update RecipeUpdate
+		if record.Descriptions != "" && record.Descriptions != recipe.Descriptions {
+			update.Descriptions = &record.Descriptions
+		}
+		if record.Instructions != "" && record.Instructions != recipe.Instructions {
+			update.Instructions = &record.Instructions
+		}
+		if record.ImageURL != "" && record.ImageURL != recipe.ImageURL {
+			update.ImageURL = &record.ImageURL
+		}
+		if record.PrepTime != nil && *record.PrepTime != recipe.PrepTime {
+			update.PrepTime = record.PrepTime
+		}
+		if record.CookTime != nil && *record.CookTime != recipe.CookTime {
+			update.CookTime = record.CookTime
+		}
+		if record.Servings != nil && *record.Servings != recipe.Servings {
+			update.Servings = record.Servings
+		}
+		if categoryId != nil && *categoryId != recipe.CategoryId {
+			update.CategoryId = categoryId
+		}
+		if columns := update.columns(); len(columns) > 0 {
+			if err := revise(recipe.ID); err != nil {
+				return err
+			}
+			if err := tx.Model(&recipe).Updates(columns).Error; err != nil {
+				return err
+			}
+			report.Recipes.Updated++
+		}
+		return nil
+	}
+
+	if categoryId == nil {
+		fail(record, "category is required for a new recipe")
+		return nil
+	}
+	recipe = models.Recipe{
+		Title:        title,
+		Descriptions: record.Descriptions,
+		Instructions: record.Instructions,
+		ImageURL:     record.ImageURL,
+		CategoryId:   *categoryId,
+	}
+	if authorId != 0 {
+		recipe.AuthorId = &authorId
+	}
+	if record.PrepTime != nil {
+		recipe.PrepTime = *record.PrepTime
+	}
+	if record.CookTime != nil {
+		recipe.CookTime = *record.CookTime
+	}
+	if record.Servings != nil {
+		recipe.Servings = *record.Servings
+	}
+	if err := tx.Omit("Category", "RecipeIngredients").Create(&recipe).Error; err != nil {
+		return err
+	}
+	revised[recipe.ID] = true
+	report.Recipes.Created++
+	return nil
+}
+
+// importLines mengganti baris bahan setiap recipe yang punya baris line di
+// file, sesuai urutan kemunculannya.
+func importLines(tx *gorm.DB, records []catalog.Record, revise func(uint) error, report *catalog.Report, fail func(catalog.Record, string, ...interface{})) error {
+	var titles []string
+	lines := make(map[string][]IngredientLine)
+	valid := make(map[string]bool)
+
+	for _, record := range records {
+		if record.Type != catalog.TypeLine {
+			continue
+		}
+		title := strings.TrimSpace(record.Recipe)
+		if title == "" {
+			fail(record, "recipe is required")
+			continue
+		}
+		if _, ok := lines[title]; !ok {
+			titles = append(titles, title)
+			valid[title] = true
+		}
+		name := strings.TrimSpace(record.Ingredient)
+		if name == "" {
+			fail(record, "ingredient is required")
+			valid[title] = false
+		}
+		lines[title] = append(lines[title], IngredientLine{Name: name, Amount: record.Amount, Unit: record.Unit})
+	}
+
+	for _, title := range titles {
+		var recipe models.Recipe
+		result := tx.Where(models.Recipe{Title: title}).Order("id").Limit(1).Find(&recipe)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			for _, record := range records {
+				if record.Type == catalog.TypeLine && strings.TrimSpace(record.Recipe) == title {
+					fail(record, "recipe %q not found", title)
+				}
+			}
+			continue
+		}
+		if !valid[title] {
+			continue
+		}
+		same, err := sameRecipeLines(tx, recipe.ID, lines[title])
+		if err != nil {
+			return err
+		}
+		if !same {
+			if err := revise(recipe.ID); err != nil {
+				return err
+			}
+		}
+		if err := replaceRecipeIngredients(tx, recipe.ID, lines[title]); err != nil {
+			return err
+		}
+		report.Lines += len(lines[title])
+	}
+	return nil
+}
+
+// sameRecipeLines memeriksa apakah baris bahan recipe sudah sama dengan
+// lines, agar mengimpor ulang hasil ekspor tidak membuat revisi baru.
+func sameRecipeLines(tx *gorm.DB, recipeId uint, lines []IngredientLine) (bool, error) {
+	var current []models.RecipeIngredient
+	err := tx.Preload("Ingredient").Where("recipe_id = ?", recipeId).Order("id").Find(&current).Error
+	if err != nil || len(current) != len(lines) {
+		return false, err
+	}
+	for i, line := range lines {
+		if current[i].Ingredient.Name != strings.TrimSpace(line.Name) ||
+			current[i].Amount != strings.TrimSpace(line.Amount) ||
+			current[i].Unit != strings.TrimSpace(line.Unit) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *catalogRepository) Export(ctx context.Context, emit func(catalog.Record) error) error {
+	db := r.db.WithContext(ctx)
+
+	var categories []models.Category
+	err := db.Order("id").FindInBatches(&categories, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, category := range categories {
+			if err := emit(catalog.Record{Type: catalog.TypeCategory, Category: category.Name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var ingredients []models.Ingredient
+	err = db.Order("id").FindInBatches(&ingredients, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, ingredient := range ingredients {
+			density := ingredient.Density
+			record := catalog.Record{Type: catalog.TypeIngredient, Ingredient: ingredient.Name}
+			if density != 0 {
+				record.Density = &density
+			}
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var recipes []models.Recipe
+	query := db.Order("id").
+		Preload("Category").
+		Preload("RecipeIngredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_ingredients.id")
+		}).
+		Preload("RecipeIngredients.Ingredient")
+	return query.FindInBatches(&recipes, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, recipe := range recipes {
+			prepTime, cookTime, servings := recipe.PrepTime, recipe.CookTime, recipe.Servings
+			err := emit(catalog.Record{
+				Type:         catalog.TypeRecipe,
+				Recipe:       recipe.Title,
+				Category:     recipe.Category.Name,
+				Descriptions: recipe.Descriptions,
+				Instructions: recipe.Instructions,
+				PrepTime:     &prepTime,
+				CookTime:     &cookTime,
+				Servings:     &servings,
+				ImageURL:     recipe.ImageURL,
+			})
+			if err != nil {
+				return err
+			}
+			for _, line := range recipe.RecipeIngredients {
+				err := emit(catalog.Record{
+					Type:       catalog.TypeLine,
+					Recipe:     recipe.Title,
+					Ingredient: line.Ingredient.Name,
+					Amount:     line.Amount,
+					Unit:       line.Unit,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}).Error
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-rest-modul/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCategoryParentNotFound dikembalikan jika parent_id tidak ada.
+	ErrCategoryParentNotFound = errors.New("parent category not found")
+	// ErrCategoryCycle dikembalikan jika category dipindah ke bawah dirinya
+	// sendiri atau ke bawah salah satu turunannya.
+	ErrCategoryCycle = errors.New("category cannot be placed under itself or its descendants")
+)
+
+// CategoryCrumb adalah satu langkah pada breadcrumb category.
+type CategoryCrumb struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryNode adalah category beserta sub-category-nya pada tree.
+type CategoryNode struct {
+	ID       uint           `json:"id"`
+	Name     string         `json:"name"`
+	ParentId *uint          `json:"parent_id"`
+	Children []CategoryNode `json:"children"`
+}
+
+type CategoryRepository interface {
+	List(ctx context.Context, opts ListOptions) ([]models.Category, int64, error)
+	FindByID(ctx context.Context, id uint) (models.Category, error)
+	FindByName(ctx context.Context, name string) (models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	Update(ctx context.Context, category *models.Category) error
+	// Delete menghapus category; sub-category-nya dipindah ke parent category
+	// yang dihapus.
+	Delete(ctx context.Context, id uint) error
+	// Tree mengembalikan semua category sebagai tree, diurutkan per nama.
+	Tree(ctx context.Context) ([]CategoryNode, error)
+	// Breadcrumbs mengembalikan jalur dari category paling atas sampai
+	// category itu sendiri untuk setiap id.
+	Breadcrumbs(ctx context.Context, ids []uint) (map[uint][]CategoryCrumb, error)
+	// DescendantIDs mengembalikan id category beserta semua turunannya.
+	DescendantIDs(ctx context.Context, id uint) ([]uint, error)
+}
+
+// categorySortable adalah field yang boleh dipakai pada parameter sort.
+var categorySortable = map[string]string{
+	"id":         "categories.id",
+	"name":       "categories.name",
+	"created_at": "categories.created_at",
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) List(ctx context.Context, opts ListOptions) ([]models.Category, int64, error) {
+	var categories []models.Category
+	db := r.db.WithContext(ctx).Model(&models.Category{})
+	total, err := findPage(db, &categories, opts, categorySortable, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Recipes")
+	})
+	return categories, total, err
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id uint) (models.Category, error) {
+	var category models.Category
+	err := r.db.WithContext(ctx).First(&category, id).Error
+	return category, translate(err)
+}
+
+func (r *categoryRepository) FindByName(ctx context.Context, name string) (models.Category, error) {
+	var category models.Category
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&category).Error
+	return category, translate(err)
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryParent(tx, category); err != nil {
+			return err
+		}
+		return tx.Omit("Recipes").Create(category).Error
+	})
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryParent(tx, category); err != nil {
+			return err
+		}
+		return tx.Omit("Recipes").Save(category).Error
+	})
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.First(&category, id).Error; err != nil {
+			return translate(err)
+		}
+		err := tx.Model(&models.Category{}).
+			Where("parent_id = ?", id).
+			Update("parent_id", category.ParentId).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Category{}, id).Error
+	})
+}
+
+// loadCategories memuat id, nama dan parent semua category. Jumlah category
+// relatif kecil sehingga tree cukup disusun di memori.
+func loadCategories(db *gorm.DB) (map[uint]models.Category, error) {
+	var categories []models.Category
+	if err := db.Select("id", "name", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	index := make(map[uint]models.Category, len(categories))
+	for _, category := range categories {
+		index[category.ID] = category
+	}
+	return index, nil
+}
+
+// checkCategoryParent memastikan parent category ada dan bukan category itu
+// sendiri atau turunannya.
+func checkCategoryParent(tx *gorm.DB, category *models.Category) error {
+	if category.ParentId == nil {
+		return nil
+	}
+	if *category.ParentId == category.ID {
+		return ErrCategoryCycle
+	}
+	index, err := loadCategories(tx)
+	if err != nil {
+		return err
+	}
+	if _, ok := index[*category.ParentId]; !ok {
+		return ErrCategoryParentNotFound
+	}
+	if category.ID == 0 {
+		return nil
+	}
+	// Telusuri ke atas dari parent baru; jika bertemu category ini berarti
+	// parent baru adalah turunannya. Batas langkah menjaga dari data yang
+	// sudah terlanjur melingkar.
+	parentId := category.ParentId
+	for steps := 0; parentId != nil && steps <= len(index); steps++ {
+		if *parentId == category.ID {
+			return ErrCategoryCycle
+		}
+		parentId = index[*parentId].ParentId
+	}
+	return nil
+}
+
+func (r *categoryRepository) Tree(ctx context.Context) ([]CategoryNode, error) {
+	index, err := loadCategories(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range index {
+		// Parent yang sudah tidak ada diperlakukan sebagai category paling atas
+		if category.ParentId == nil || index[*category.ParentId].ID == 0 {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentId] = append(children[*category.ParentId], category)
+	}
+
+	visited := make(map[uint]bool, len(index))
+	var build func(categories []models.Category) []CategoryNode
+	build = func(categories []models.Category) []CategoryNode {
+		sort.Slice(categories, func(i, j int) bool {
+			if categories[i].Name != categories[j].Name {
+				return categories[i].Name < categories[j].Name
+			}
+			return categories[i].ID < categories[j].ID
+		})
+		nodes := make([]CategoryNode, 0, len(categories))
+		for _, category := range categories {
+			if visited[category.ID] {
+				continue
+			}
+			visited[category.ID] = true
+			nodes = append(nodes, CategoryNode{
+				ID:       category.ID,
+				Name:     category.Name,
+				ParentId: category.ParentId,
+				Children: build(children[category.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots), nil
+}
+
+func (r *categoryRepository) Breadcrumbs(ctx context.Context, ids []uint) (map[uint][]CategoryCrumb, error) {
+	index, err := loadCategories(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint][]CategoryCrumb, len(ids))
+	for _, id := range ids {
+		var crumbs []CategoryCrumb
+		current, ok := index[id]
+		for steps := 0; ok && steps <= len(index); steps++ {
+			crumbs = append(crumbs, CategoryCrumb{ID: current.ID, Name: current.Name})
+			if current.ParentId == nil {
+				break
+			}
+			current, ok = index[*current.ParentId]
+		}
+		// Dibalik agar urut dari category paling atas
+		for i, j := 0, len(crumbs)-1; i < j; i, j = i+1, j-1 {
+			crumbs[i], crumbs[j] = crumbs[j], crumbs[i]
+		}
+		result[id] = crumbs
+	}
+	return result, nil
+}
+
+func (r *categoryRepository) DescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	index, err := loadCategories(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := index[id]; !ok {
+		return nil, ErrNotFound
+	}
+
+	children := make(map[uint][]uint)
+	for _, category := range index {
+		if category.ParentId != nil {
+			children[*category.ParentId] = append(children[*category.ParentId], category.ID)
+		}
+	}
+
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCollectionEntry dikembalikan jika recipe yang ditambahkan tidak
+	// ada atau urutan baru tidak cocok dengan isi collection.
+	ErrInvalidCollectionEntry = errors.New("invalid collection entry")
+	// ErrCollectionEntryExists dikembalikan jika recipe sudah ada di collection.
+	ErrCollectionEntryExists = errors.New("recipe already in collection")
+)
+
+// CollectionUpdate berisi field collection yang akan diubah. Field nil tidak disentuh.
+type CollectionUpdate struct {
+	Name        *string
+	Description *string
+}
+
+// CollectionRepository hanya mengakses collection milik userId; collection
+// milik user lain dianggap tidak ada (ErrNotFound).
+type CollectionRepository interface {
+	List(ctx context.Context, userId uint, opts ListOptions) ([]models.Collection, int64, error)
+	FindByID(ctx context.Context, userId, id uint) (models.Collection, error)
+	// FindByShareToken dipakai untuk link share dan tidak memeriksa pemilik.
+	FindByShareToken(ctx context.Context, token string) (models.Collection, error)
+	Create(ctx context.Context, collection *models.Collection) error
+	Update(ctx context.Context, userId, id uint, update CollectionUpdate) (models.Collection, error)
+	Delete(ctx context.Context, userId, id uint) error
+	AddRecipe(ctx context.Context, userId, id, recipeId uint) (models.Collection, error)
+	RemoveRecipe(ctx context.Context, userId, id, recipeId uint) (models.Collection, error)
+	// Reorder mengurutkan ulang entry; recipeIds harus berisi tepat semua
+	// recipe yang ada di collection.
+	Reorder(ctx context.Context, userId, id uint, recipeIds []uint) (models.Collection, error)
+	// Share membuat token share jika belum ada, Unshare menghapusnya sehingga
+	// link lama tidak berlaku lagi.
+	Share(ctx context.Context, userId, id uint) (models.Collection, error)
+	Unshare(ctx context.Context, userId, id uint) (models.Collection, error)
+}
+
+var collectionSortable = map[string]string{
+	"id":         "collections.id",
+	"name":       "collections.name",
+	"created_at": "collections.created_at",
+	"updated_at": "collections.updated_at",
+}
+
+type collectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+func preloadCollection(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("collection_entries.position").Order("collection_entries.id")
+		}).
+		Preload("Entries.Recipe")
+}
+
+// newShareToken membuat token acak yang aman dipakai di URL.
+func newShareToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// findOwnedCollection memastikan collection ada dan dimiliki userId.
+func findOwnedCollection(tx *gorm.DB, userId, id uint) (models.Collection, error) {
+	var collection models.Collection
+	err := tx.Where("user_id = ?", userId).First(&collection, id).Error
+	return collection, translate(err)
+}
+
+func (r *collectionRepository) List(ctx context.Context, userId uint, opts ListOptions) ([]models.Collection, int64, error) {
+	var collections []models.Collection
+	db := r.db.WithContext(ctx).Model(&models.Collection{}).Where("collections.user_id = ?", userId)
+	total, err := findPage(db, &collections, opts, collectionSortable, preloadCollection)
+	return collections, total, err
+}
+
+func (r *collectionRepository) FindByID(ctx context.Context, userId, id uint) (models.Collection, error) {
+	var collection models.Collection
+	err := preloadCollection(r.db.WithContext(ctx)).Where("user_id = ?", userId).First(&collection, id).Error
+	return collection, translate(err)
+}
+
+func (r *collectionRepository) FindByShareToken(ctx context.Context, token string) (models.Collection, error) {
+	var collection models.Collection
+	err := preloadCollection(r.db.WithContext(ctx)).Where("share_token = ?", token).First(&collection).Error
+	return collection, translate(err)
+}
+
+func (r *collectionRepository) Create(ctx context.Context, collection *models.Collection) error {
+	return r.db.WithContext(ctx).Omit("Entries").Create(collection).Error
+}
+
+func (r *collectionRepository) Update(ctx context.Context, userId, id uint, update CollectionUpdate) (models.Collection, error) {
+	collection, err := findOwnedCollection(r.db.WithContext(ctx), userId, id)
+	if err != nil {
+		return collection, err
+	}
+
+	columns := make(map[string]interface{})
+	if update.Name != nil {
+		columns["name"] = *update.Name
+	}
+	if update.Description != nil {
+		columns["description"] = *update.Description
+	}
+	if len(columns) > 0 {
+		if err := r.db.WithContext(ctx).Model(&collection).Updates(columns).Error; err != nil {
+			return collection, err
+		}
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+// Delete menghapus collection beserta semua entry-nya.
+func (r *collectionRepository) Delete(ctx context.Context, userId, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedCollection(tx, userId, id); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("collection_id = ?", id).Delete(&models.CollectionEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Collection{}, id).Error
+	})
+}
+
+// AddRecipe menambahkan recipe di akhir collection.
+func (r *collectionRepository) AddRecipe(ctx context.Context, userId, id, recipeId uint) (models.Collection, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedCollection(tx, userId, id); err != nil {
+			return err
+		}
+		if err := tx.Select("id").First(&models.Recipe{}, recipeId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: recipe %d not found", ErrInvalidCollectionEntry, recipeId)
+			}
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.CollectionEntry{}).
+			Where("collection_id = ? AND recipe_id = ?", id, recipeId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCollectionEntryExists
+		}
+
+		var last struct{ Position int }
+		err = tx.Model(&models.CollectionEntry{}).
+			Select("COALESCE(MAX(position), 0) AS position").
+			Where("collection_id = ?", id).
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		entry := models.CollectionEntry{CollectionId: id, RecipeId: recipeId, Position: last.Position + 1}
+		if err := tx.Omit("Recipe").Create(&entry).Error; err != nil {
+			return err
+		}
+		return touchCollection(tx, id)
+	})
+	if err != nil {
+		return models.Collection{}, err
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+func (r *collectionRepository) RemoveRecipe(ctx context.Context, userId, id, recipeId uint) (models.Collection, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedCollection(tx, userId, id); err != nil {
+			return err
+		}
+		result := tx.Unscoped().
+			Where("collection_id = ? AND recipe_id = ?", id, recipeId).
+			Delete(&models.CollectionEntry{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return touchCollection(tx, id)
+	})
+	if err != nil {
+		return models.Collection{}, err
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+func (r *collectionRepository) Reorder(ctx context.Context, userId, id uint, recipeIds []uint) (models.Collection, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedCollection(tx, userId, id); err != nil {
+			return err
+		}
+
+		var entries []models.CollectionEntry
+		if err := tx.Where("collection_id = ?", id).Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(recipeIds) != len(entries) {
+			return fmt.Errorf("%w: expected %d recipe ids, got %d", ErrInvalidCollectionEntry, len(entries), len(recipeIds))
+		}
+
+		entryIds := make(map[uint]uint, len(entries))
+		for _, entry := range entries {
+			entryIds[entry.RecipeId] = entry.ID
+		}
+		for i, recipeId := range recipeIds {
+			entryId, ok := entryIds[recipeId]
+			if !ok {
+				return fmt.Errorf("%w: recipe %d is not in this collection or listed twice", ErrInvalidCollectionEntry, recipeId)
+			}
+			delete(entryIds, recipeId)
+			if err := tx.Model(&models.CollectionEntry{}).Where("id = ?", entryId).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return touchCollection(tx, id)
+	})
+	if err != nil {
+		return models.Collection{}, err
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+func (r *collectionRepository) Share(ctx context.Context, userId, id uint) (models.Collection, error) {
+	collection, err := findOwnedCollection(r.db.WithContext(ctx), userId, id)
+	if err != nil {
+		return collection, err
+	}
+	if collection.ShareToken == nil {
+		token, err := newShareToken()
+		if err != nil {
+			return collection, err
+		}
+		if err := r.db.WithContext(ctx).Model(&collection).Update("share_token", token).Error; err != nil {
+			return collection, err
+		}
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+func (r *collectionRepository) Unshare(ctx context.Context, userId, id uint) (models.Collection, error) {
+	collection, err := findOwnedCollection(r.db.WithContext(ctx), userId, id)
+	if err != nil {
+		return collection, err
+	}
+	if err := r.db.WithContext(ctx).Model(&collection).Update("share_token", nil).Error; err != nil {
+		return collection, err
+	}
+	return r.FindByID(ctx, userId, id)
+}
+
+// touchCollection memperbarui updated_at collection saat isinya berubah.
+func touchCollection(tx *gorm.DB, id uint) error {
+	return tx.Model(&models.Collection{}).Where("id = ?", id).Update("updated_at", time.Now()).Error
+}
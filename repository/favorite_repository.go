@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"go-rest-modul/models"
+
+	"gorm.io/gorm"
+)
+
+type FavoriteRepository interface {
+	List(ctx context.Context, userId uint, opts ListOptions) ([]models.Favorite, int64, error)
+	// Add bersifat idempotent; created bernilai false jika recipe sudah
+	// menjadi favorite sebelumnya.
+	Add(ctx context.Context, userId, recipeId uint) (favorite models.Favorite, created bool, err error)
+	Remove(ctx context.Context, userId, recipeId uint) error
+}
+
+var favoriteSortable = map[string]string{
+	"id":         "favorites.id",
+	"created_at": "favorites.created_at",
+}
+
+type favoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+func (r *favoriteRepository) List(ctx context.Context, userId uint, opts ListOptions) ([]models.Favorite, int64, error) {
+	var favorites []models.Favorite
+	db := r.db.WithContext(ctx).Model(&models.Favorite{}).Where("favorites.user_id = ?", userId)
+	total, err := findPage(db, &favorites, opts, favoriteSortable, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Recipe")
+	})
+	return favorites, total, err
+}
+
+func (r *favoriteRepository) Add(ctx context.Context, userId, recipeId uint) (models.Favorite, bool, error) {
+	var favorite models.Favorite
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Recipe{}, recipeId).Error; err != nil {
+			return translate(err)
+		}
+		result := tx.Where(models.Favorite{UserId: userId, RecipeId: recipeId}).Limit(1).Find(&favorite)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+		favorite = models.Favorite{UserId: userId, RecipeId: recipeId}
+		created = true
+		return tx.Omit("Recipe").Create(&favorite).Error
+	})
+	if err != nil {
+		return favorite, false, err
+	}
+	err = r.db.WithContext(ctx).Preload("Recipe").First(&favorite, favorite.ID).Error
+	return favorite, created, translate(err)
+}
+
+func (r *favoriteRepository) Remove(ctx context.Context, userId, recipeId uint) error {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND recipe_id = ?", userId, recipeId).
+		Delete(&models.Favorite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
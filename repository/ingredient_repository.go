@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"go-rest-modul/models"
+
+	"gorm.io/gorm"
+)
+
+type IngredientRepository interface {
+	List(ctx context.Context) ([]models.Ingredient, error)
+	FindByID(ctx context.Context, id uint) (models.Ingredient, error)
+	FindByName(ctx context.Context, name string) (models.Ingredient, error)
+	Create(ctx context.Context, ingredient *models.Ingredient) error
+	Update(ctx context.Context, ingredient *models.Ingredient) error
+	Delete(ctx context.Context, id uint) error
+	// CountUsage menghitung jumlah baris RecipeIngredient yang memakai bahan
+	// ini, tanpa recipe yang sudah dihapus.
+	CountUsage(ctx context.Context, id uint) (int64, error)
+	// ListRecipes mengembalikan resep (tanpa duplikat) yang memakai bahan ini.
+	ListRecipes(ctx context.Context, id uint) ([]models.Recipe, error)
+}
+
+type ingredientRepository struct {
+	db *gorm.DB
+}
+
+func NewIngredientRepository(db *gorm.DB) IngredientRepository {
+	return &ingredientRepository{db: db}
+}
+
+func (r *ingredientRepository) List(ctx context.Context) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.WithContext(ctx).Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *ingredientRepository) FindByID(ctx context.Context, id uint) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := r.db.WithContext(ctx).First(&ingredient, id).Error
+	return ingredient, translate(err)
+}
+
+func (r *ingredientRepository) FindByName(ctx context.Context, name string) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&ingredient).Error
+	return ingredient, translate(err)
+}
+
+func (r *ingredientRepository) Create(ctx context.Context, ingredient *models.Ingredient) error {
+	return r.db.WithContext(ctx).Omit("RecipeIngredients").Create(ingredient).Error
+}
+
+func (r *ingredientRepository) Update(ctx context.Context, ingredient *models.Ingredient) error {
+	return r.db.WithContext(ctx).Omit("RecipeIngredients").Save(ingredient).Error
+}
+
+// Delete melakukan soft delete bahan dan menghapus data gizinya. Baris
+// bahan pada recipe yang sudah dihapus, revisi dan daftar belanja masih
+// merujuk bahan ini, jadi barisnya tetap disimpan.
+func (r *ingredientRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("ingredient_id = ?", id).Delete(&models.IngredientNutrient{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Ingredient{}, id).Error
+	})
+}
+
+func (r *ingredientRepository) CountUsage(ctx context.Context, id uint) (int64, error) {
+	var used int64
+	err := r.db.WithContext(ctx).Model(&models.RecipeIngredient{}).
+		Joins("JOIN recipes ON recipes.id = recipe_ingredients.recipe_id AND recipes.deleted_at IS NULL").
+		Where("recipe_ingredients.ingredient_id = ?", id).
+		Count(&used).Error
+	return used, err
+}
+
+func (r *ingredientRepository) ListRecipes(ctx context.Context, id uint) ([]models.Recipe, error) {
+	var ingredient models.Ingredient
+	err := r.db.WithContext(ctx).
+		Preload("RecipeIngredients").
+		Preload("RecipeIngredients.Recipe").
+		Preload("RecipeIngredients.Recipe.Category").
+		First(&ingredient, id).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	// Satu resep bisa memakai bahan yang sama lebih dari sekali
+	recipes := []models.Recipe{}
+	seen := make(map[uint]bool)
+	for _, line := range ingredient.RecipeIngredients {
+		// Resep yang sudah soft-delete tidak ikut ter-preload
+		if line.Recipe.ID == 0 || seen[line.Recipe.ID] {
+			continue
+		}
+		seen[line.Recipe.ID] = true
+		recipes = append(recipes, line.Recipe)
+	}
+	return recipes, nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DateLayout adalah format tanggal pada payload meal plan.
+const DateLayout = "2006-01-02"
+
+// MealSlots adalah slot makan yang boleh dipakai, sesuai urutan dalam sehari.
+var MealSlots = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// ErrInvalidMealEntry dikembalikan jika entry meal plan tidak valid.
+var ErrInvalidMealEntry = errors.New("invalid meal plan entry")
+
+// MealEntry adalah satu entry pada payload meal plan.
+type MealEntry struct {
+	Date     string `json:"date"`
+	Slot     string `json:"slot"`
+	RecipeId uint   `json:"recipe_id"`
+	Servings int    `json:"servings"`
+}
+
+// MealPlanUpdate berisi field meal plan yang akan diubah. Field nil tidak disentuh.
+type MealPlanUpdate struct {
+	Name      *string
+	StartDate *time.Time
+	// nil berarti entry tidak diubah, slice kosong berarti dihapus semua
+	Entries *[]MealEntry
+}
+
+// MealPlanRepository hanya mengakses meal plan milik userId; meal plan milik
+// user lain dianggap tidak ada (ErrNotFound). Create memakai plan.UserId.
+type MealPlanRepository interface {
+	List(ctx context.Context, userId uint, opts ListOptions) ([]models.MealPlan, int64, error)
+	FindByID(ctx context.Context, userId, id uint) (models.MealPlan, error)
+	Create(ctx context.Context, plan *models.MealPlan, entries []MealEntry) error
+	Update(ctx context.Context, userId, id uint, update MealPlanUpdate) (models.MealPlan, error)
+	Delete(ctx context.Context, userId, id uint) error
+	AddEntry(ctx context.Context, userId, planId uint, entry MealEntry) (models.MealPlanEntry, error)
+	DeleteEntry(ctx context.Context, userId, planId, entryId uint) error
+	// ListEntries mengembalikan entry pada rentang tanggal [from, to).
+	ListEntries(ctx context.Context, userId, planId uint, from, to time.Time) ([]models.MealPlanEntry, error)
+}
+
+var mealPlanSortable = map[string]string{
+	"id":         "meal_plans.id",
+	"name":       "meal_plans.name",
+	"start_date": "meal_plans.start_date",
+	"created_at": "meal_plans.created_at",
+}
+
+type mealPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewMealPlanRepository(db *gorm.DB) MealPlanRepository {
+	return &mealPlanRepository{db: db}
+}
+
+// ParseDate membaca tanggal dengan format DateLayout.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(DateLayout, strings.TrimSpace(s))
+}
+
+// orderEntries mengurutkan entry per tanggal lalu per slot makan.
+func orderEntries(db *gorm.DB) *gorm.DB {
+	return db.Order("meal_plan_entries.date").
+		Order("CASE meal_plan_entries.slot WHEN 'breakfast' THEN 1 WHEN 'lunch' THEN 2 WHEN 'dinner' THEN 3 ELSE 4 END").
+		Order("meal_plan_entries.id")
+}
+
+func preloadMealPlan(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Entries", orderEntries).
+		Preload("Entries.Recipe")
+}
+
+// findOwnedMealPlan memastikan meal plan ada dan dimiliki userId.
+func findOwnedMealPlan(tx *gorm.DB, userId, id uint) (models.MealPlan, error) {
+	var plan models.MealPlan
+	err := tx.Where("user_id = ?", userId).First(&plan, id).Error
+	return plan, translate(err)
+}
+
+func (r *mealPlanRepository) List(ctx context.Context, userId uint, opts ListOptions) ([]models.MealPlan, int64, error) {
+	var plans []models.MealPlan
+	db := r.db.WithContext(ctx).Model(&models.MealPlan{}).Where("meal_plans.user_id = ?", userId)
+	total, err := findPage(db, &plans, opts, mealPlanSortable, preloadMealPlan)
+	return plans, total, err
+}
+
+func (r *mealPlanRepository) FindByID(ctx context.Context, userId, id uint) (models.MealPlan, error) {
+	var plan models.MealPlan
+	err := preloadMealPlan(r.db.WithContext(ctx)).Where("user_id = ?", userId).First(&plan, id).Error
+	return plan, translate(err)
+}
+
+func (r *mealPlanRepository) Create(ctx context.Context, plan *models.MealPlan, entries []MealEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Entries").Create(plan).Error; err != nil {
+			return err
+		}
+		for i, entry := range entries {
+			if _, err := createMealEntry(tx, plan.ID, entry, i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return preloadMealPlan(r.db.WithContext(ctx)).First(plan, plan.ID).Error
+}
+
+func (r *mealPlanRepository) Update(ctx context.Context, userId, id uint, update MealPlanUpdate) (models.MealPlan, error) {
+	var plan models.MealPlan
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if plan, err = findOwnedMealPlan(tx, userId, id); err != nil {
+			return err
+		}
+
+		columns := make(map[string]interface{})
+		if update.Name != nil {
+			columns["name"] = *update.Name
+		}
+		if update.StartDate != nil {
+			columns["start_date"] = *update.StartDate
+		}
+		if len(columns) > 0 {
+			if err := tx.Model(&plan).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+
+		if update.Entries != nil {
+			if err := tx.Unscoped().Where("meal_plan_id = ?", id).Delete(&models.MealPlanEntry{}).Error; err != nil {
+				return err
+			}
+			for i, entry := range *update.Entries {
+				if _, err := createMealEntry(tx, id, entry, i+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return plan, err
+	}
+
+	err = preloadMealPlan(r.db.WithContext(ctx)).First(&plan, id).Error
+	return plan, translate(err)
+}
+
+// Delete menghapus meal plan beserta semua entry-nya.
+func (r *mealPlanRepository) Delete(ctx context.Context, userId, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedMealPlan(tx, userId, id); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("meal_plan_id = ?", id).Delete(&models.MealPlanEntry{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Delete(&models.MealPlan{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *mealPlanRepository) AddEntry(ctx context.Context, userId, planId uint, entry MealEntry) (models.MealPlanEntry, error) {
+	db := r.db.WithContext(ctx)
+	if _, err := findOwnedMealPlan(db, userId, planId); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	created, err := createMealEntry(db, planId, entry, 1)
+	if err != nil {
+		return created, err
+	}
+	err = db.Preload("Recipe").First(&created, created.ID).Error
+	return created, translate(err)
+}
+
+func (r *mealPlanRepository) DeleteEntry(ctx context.Context, userId, planId, entryId uint) error {
+	if _, err := findOwnedMealPlan(r.db.WithContext(ctx), userId, planId); err != nil {
+		return err
+	}
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("meal_plan_id = ?", planId).
+		Delete(&models.MealPlanEntry{}, entryId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mealPlanRepository) ListEntries(ctx context.Context, userId, planId uint, from, to time.Time) ([]models.MealPlanEntry, error) {
+	if _, err := findOwnedMealPlan(r.db.WithContext(ctx), userId, planId); err != nil {
+		return nil, err
+	}
+	var entries []models.MealPlanEntry
+	err := orderEntries(r.db.WithContext(ctx)).
+		Preload("Recipe").
+		Where("meal_plan_id = ? AND date >= ? AND date < ?", planId, from, to).
+		Find(&entries).Error
+	return entries, err
+}
+
+// createMealEntry memvalidasi lalu menyimpan satu entry. line dipakai untuk
+// pesan error pada payload berisi banyak entry.
+func createMealEntry(tx *gorm.DB, planId uint, entry MealEntry, line int) (models.MealPlanEntry, error) {
+	date, err := ParseDate(entry.Date)
+	if err != nil {
+		return models.MealPlanEntry{}, fmt.Errorf("%w: date must use format %s (entry %d)", ErrInvalidMealEntry, DateLayout, line)
+	}
+
+	slot := strings.ToLower(strings.TrimSpace(entry.Slot))
+	valid := false
+	for _, s := range MealSlots {
+		if s == slot {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return models.MealPlanEntry{}, fmt.Errorf("%w: slot must be one of %s (entry %d)", ErrInvalidMealEntry, strings.Join(MealSlots, ", "), line)
+	}
+
+	if entry.Servings < 0 {
+		return models.MealPlanEntry{}, fmt.Errorf("%w: servings cannot be negative (entry %d)", ErrInvalidMealEntry, line)
+	}
+
+	if err := tx.First(&models.Recipe{}, entry.RecipeId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.MealPlanEntry{}, fmt.Errorf("%w: recipe %d not found (entry %d)", ErrInvalidMealEntry, entry.RecipeId, line)
+		}
+		return models.MealPlanEntry{}, err
+	}
+
+	created := models.MealPlanEntry{
+		MealPlanId: planId,
+		Date:       date,
+		Slot:       slot,
+		RecipeId:   entry.RecipeId,
+		Servings:   entry.Servings,
+	}
+	err = tx.Omit("Recipe").Create(&created).Error
+	return created, err
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-rest-modul/models"
+
+	"gorm.io/gorm"
+)
+
+type NutrientRepository interface {
+	FindByIngredient(ctx context.Context, ingredientId uint) (models.IngredientNutrient, error)
+	// FindByIngredients mengembalikan data gizi dengan key IngredientId.
+	// Bahan tanpa data gizi tidak ada di hasil.
+	FindByIngredients(ctx context.Context, ingredientIds []uint) (map[uint]models.IngredientNutrient, error)
+	// Save membuat atau mengganti data gizi milik nutrient.IngredientId.
+	Save(ctx context.Context, nutrient *models.IngredientNutrient) error
+}
+
+type nutrientRepository struct {
+	db *gorm.DB
+}
+
+func NewNutrientRepository(db *gorm.DB) NutrientRepository {
+	return &nutrientRepository{db: db}
+}
+
+func (r *nutrientRepository) FindByIngredient(ctx context.Context, ingredientId uint) (models.IngredientNutrient, error) {
+	var nutrient models.IngredientNutrient
+	err := r.db.WithContext(ctx).Where("ingredient_id = ?", ingredientId).First(&nutrient).Error
+	return nutrient, translate(err)
+}
+
+func (r *nutrientRepository) FindByIngredients(ctx context.Context, ingredientIds []uint) (map[uint]models.IngredientNutrient, error) {
+	table := make(map[uint]models.IngredientNutrient)
+	if len(ingredientIds) == 0 {
+		return table, nil
+	}
+
+	var nutrients []models.IngredientNutrient
+	if err := r.db.WithContext(ctx).Where("ingredient_id IN ?", ingredientIds).Find(&nutrients).Error; err != nil {
+		return nil, err
+	}
+	for _, nutrient := range nutrients {
+		table[nutrient.IngredientId] = nutrient
+	}
+	return table, nil
+}
+
+func (r *nutrientRepository) Save(ctx context.Context, nutrient *models.IngredientNutrient) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.IngredientNutrient
+		err := tx.Where("ingredient_id = ?", nutrient.IngredientId).First(&existing).Error
+		switch {
+		case err == nil:
+			nutrient.ID = existing.ID
+			nutrient.CreatedAt = existing.CreatedAt
+			return tx.Save(nutrient).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return tx.Create(nutrient).Error
+		default:
+			return err
+		}
+	})
+}
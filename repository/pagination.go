@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	// ErrInvalidSort dikembalikan jika kolom sort tidak diizinkan.
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor dikembalikan jika ID pada parameter after tidak ada.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ListOptions mengatur pagination dan urutan pada query list. Jika After
+// diisi, pagination memakai cursor (baris setelah ID tersebut) dan Page diabaikan.
+type ListOptions struct {
+	Page     int
+	PageSize int
+	After    uint
+	// Sort berisi nama field, diawali "-" untuk urutan descending
+	Sort string
+}
+
+// Normalize mengisi nilai default dan membatasi ukuran halaman.
+func (o ListOptions) Normalize() ListOptions {
+	if o.Page < 1 {
+		o.Page = 1
+	}
+	if o.PageSize < 1 {
+		o.PageSize = DefaultPageSize
+	}
+	if o.PageSize > MaxPageSize {
+		o.PageSize = MaxPageSize
+	}
+	return o
+}
+
+// findPage menghitung total baris dari query db lalu mengambil satu halaman
+// ke dest. sortable memetakan nama field sort ke kolom bertabel, dan harus
+// berisi "id" sebagai urutan default sekaligus penentu urutan yang stabil.
+func findPage(db *gorm.DB, dest interface{}, opts ListOptions, sortable map[string]string, preload func(*gorm.DB) *gorm.DB) (int64, error) {
+	opts = opts.Normalize()
+
+	field, desc := strings.TrimPrefix(opts.Sort, "-"), strings.HasPrefix(opts.Sort, "-")
+	if field == "" {
+		field = "id"
+	}
+	column, ok := sortable[field]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSort, opts.Sort)
+	}
+	idColumn := sortable["id"]
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	query := db.Session(&gorm.Session{})
+	if opts.After != 0 {
+		cursor, err := cursorValue(db, column, idColumn, opts.After)
+		if err != nil {
+			return 0, err
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		if column == idColumn {
+			query = query.Where(fmt.Sprintf("%s %s ?", idColumn, op), opts.After)
+		} else {
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s > ?))", column, op, column, idColumn), cursor, cursor, opts.After)
+		}
+	} else {
+		query = query.Offset((opts.Page - 1) * opts.PageSize)
+	}
+
+	order := column
+	if desc {
+		order += " DESC"
+	}
+	if column != idColumn {
+		order += ", " + idColumn
+	}
+	query = query.Order(order).Limit(opts.PageSize)
+
+	if preload != nil {
+		query = preload(query)
+	}
+	return total, query.Find(dest).Error
+}
+
+// cursorValue mengambil nilai kolom sort milik baris cursor. Query dasar
+// dipakai ulang supaya join dan filter ikut berlaku, sehingga cursor harus
+// merupakan bagian dari hasil list itu sendiri.
+func cursorValue(db *gorm.DB, column, idColumn string, id uint) (interface{}, error) {
+	var value interface{}
+	err := db.Session(&gorm.Session{}).
+		Select(column).
+		Where(idColumn+" = ?", id).
+		Limit(1).
+		Row().Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCursor, id)
+	}
+	return value, err
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"go-rest-modul/models"
+	"strings"
+)
+
+// CookableRecipe adalah recipe yang bisa (atau hampir bisa) dibuat dengan
+// bahan yang tersedia, beserta baris bahan yang masih kurang.
+type CookableRecipe struct {
+	models.Recipe
+	Coverage     float64                   `json:"coverage"`
+	MissingCount int                       `json:"missing_count"`
+	Missing      []models.RecipeIngredient `json:"missing"`
+}
+
+// CookableQuery berisi bahan yang dimiliki pengguna, lewat ID atau nama.
+type CookableQuery struct {
+	IngredientIds   []uint
+	IngredientNames []string
+	// MaxMissing adalah jumlah baris bahan maksimal yang boleh kurang
+	MaxMissing int
+}
+
+func (r *recipeRepository) ListCookable(ctx context.Context, query CookableQuery, opts ListOptions) ([]CookableRecipe, int64, error) {
+	opts = opts.Normalize()
+	db := r.db.WithContext(ctx)
+
+	available, err := r.resolveIngredientIds(ctx, query)
+	if err != nil || len(available) == 0 {
+		return nil, 0, err
+	}
+
+	// Hitung per recipe berapa baris bahan yang tersedia. Recipe tanpa satu
+	// pun bahan yang cocok tidak ditampilkan.
+	matched := "SUM(CASE WHEN recipe_ingredients.ingredient_id IN ? THEN 1 ELSE 0 END)"
+	coverage := db.Table("recipe_ingredients").
+		Select("recipe_ingredients.recipe_id AS recipe_id, COUNT(*) - "+matched+" AS missing, 1.0 * "+matched+" / COUNT(*) AS coverage", available, available).
+		Joins("JOIN recipes ON recipes.id = recipe_ingredients.recipe_id AND recipes.deleted_at IS NULL").
+		Where("recipe_ingredients.deleted_at IS NULL").
+		Group("recipe_ingredients.recipe_id").
+		Having(matched+" > 0 AND COUNT(*) - "+matched+" <= ?", available, available, query.MaxMissing)
+
+	var total int64
+	if err := db.Table("(?) AS cookable", coverage).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		RecipeId uint
+		Missing  int
+		Coverage float64
+	}
+	err = db.Table("(?) AS cookable", coverage).
+		Order("missing, coverage DESC, recipe_id").
+		Offset((opts.Page - 1) * opts.PageSize).
+		Limit(opts.PageSize).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, total, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.RecipeId
+	}
+	var recipes []models.Recipe
+	if err := preloadRecipe(db).Find(&recipes, ids).Error; err != nil {
+		return nil, total, err
+	}
+	byId := make(map[uint]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byId[recipe.ID] = recipe
+	}
+
+	have := make(map[uint]bool, len(available))
+	for _, id := range available {
+		have[id] = true
+	}
+
+	results := make([]CookableRecipe, 0, len(rows))
+	for _, row := range rows {
+		recipe, ok := byId[row.RecipeId]
+		if !ok {
+			continue
+		}
+		missing := []models.RecipeIngredient{}
+		for _, line := range recipe.RecipeIngredients {
+			if !have[line.IngredientId] {
+				missing = append(missing, line)
+			}
+		}
+		results = append(results, CookableRecipe{
+			Recipe:       recipe,
+			Coverage:     row.Coverage,
+			MissingCount: row.Missing,
+			Missing:      missing,
+		})
+	}
+	return results, total, nil
+}
+
+// resolveIngredientIds menggabungkan ID bahan dan ID dari nama bahan
+// (tanpa membedakan huruf besar/kecil). Nama yang tidak dikenal diabaikan.
+func (r *recipeRepository) resolveIngredientIds(ctx context.Context, query CookableQuery) ([]uint, error) {
+	ids := append([]uint{}, query.IngredientIds...)
+
+	var names []string
+	for _, name := range query.IngredientNames {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		var found []uint
+		err := r.db.WithContext(ctx).Model(&models.Ingredient{}).
+			Where("LOWER(name) IN ?", names).
+			Pluck("id", &found).Error
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, found...)
+	}
+	return ids, nil
+}
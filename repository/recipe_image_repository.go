@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"go-rest-modul/models"
+
+	"gorm.io/gorm"
+)
+
+// RecipeImageRepository hanya mencatat gambar di database; file-nya
+// disimpan dan dihapus oleh pemanggil lewat package storage. Perubahan
+// gambar tidak dicatat sebagai revisi: file lama langsung dihapus dan
+// Restore memang tidak memulihkan ImageURL.
+type RecipeImageRepository interface {
+	// Replace mengganti semua gambar recipe dan mengisi ImageURL dengan
+	// imageURL. Gambar lama dikembalikan agar file-nya bisa dihapus.
+	Replace(ctx context.Context, recipeId uint, imageURL string, images []models.RecipeImage) ([]models.RecipeImage, error)
+	// Clear menghapus semua gambar recipe dan mengosongkan ImageURL.
+	Clear(ctx context.Context, recipeId uint) ([]models.RecipeImage, error)
+}
+
+type recipeImageRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeImageRepository(db *gorm.DB) RecipeImageRepository {
+	return &recipeImageRepository{db: db}
+}
+
+func (r *recipeImageRepository) Replace(ctx context.Context, recipeId uint, imageURL string, images []models.RecipeImage) ([]models.RecipeImage, error) {
+	for i := range images {
+		images[i].RecipeId = recipeId
+	}
+	return r.swap(ctx, recipeId, images, imageURL)
+}
+
+func (r *recipeImageRepository) Clear(ctx context.Context, recipeId uint) ([]models.RecipeImage, error) {
+	return r.swap(ctx, recipeId, nil, "")
+}
+
+// swap menghapus gambar lama, menyimpan images, lalu mengubah image_url
+// dalam satu transaksi.
+func (r *recipeImageRepository) swap(ctx context.Context, recipeId uint, images []models.RecipeImage, imageURL string) ([]models.RecipeImage, error) {
+	var old []models.RecipeImage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Recipe{}, recipeId).Error; err != nil {
+			return translate(err)
+		}
+		if err := tx.Where("recipe_id = ?", recipeId).Find(&old).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("recipe_id = ?", recipeId).Delete(&models.RecipeImage{}).Error; err != nil {
+			return err
+		}
+		if len(images) > 0 {
+			if err := tx.Create(&images).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Recipe{}).Where("id = ?", recipeId).Update("image_url", imageURL).Error
+	})
+	return old, err
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// IngredientLine adalah satu baris bahan pada payload create/update recipe.
+// Bahan dicari lewat ingredient_id, atau lewat name (dibuat jika belum ada).
+type IngredientLine struct {
+	IngredientId uint   `json:"ingredient_id"`
+	Name         string `json:"name"`
+	Amount       string `json:"amount"`
+	Unit         string `json:"unit"`
+}
+
+// RecipeUpdate berisi field recipe yang akan diubah. Field nil tidak disentuh.
+type RecipeUpdate struct {
+	Title        *string
+	Descriptions *string
+	Instructions *string
+	PrepTime     *int
+	CookTime     *int
+	Servings     *int
+	ImageURL     *string
+	CategoryId   *uint
+	// nil berarti baris bahan tidak diubah, slice kosong berarti dihapus semua
+	Ingredients *[]IngredientLine
+	// nil berarti tag tidak diubah, slice kosong berarti semua tag dilepas
+	Tags *[]string
+	// EditorId adalah user yang melakukan update, dicatat pada revisi
+	EditorId *uint
+}
+
+// IsEmpty bernilai true jika tidak ada field yang akan diubah.
+func (u RecipeUpdate) IsEmpty() bool {
+	return len(u.columns()) == 0 && u.Ingredients == nil && u.Tags == nil
+}
+
+func (u RecipeUpdate) columns() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if u.Title != nil {
+		updates["title"] = *u.Title
+	}
+	if u.Descriptions != nil {
+		updates["descriptions"] = *u.Descriptions
+	}
+	if u.Instructions != nil {
+		updates["instructions"] = *u.Instructions
+	}
+	if u.PrepTime != nil {
+		updates["prep_time"] = *u.PrepTime
+	}
+	if u.CookTime != nil {
+		updates["cook_time"] = *u.CookTime
+	}
+	if u.Servings != nil {
+		updates["servings"] = *u.Servings
+	}
+	if u.ImageURL != nil {
+		updates["image_url"] = *u.ImageURL
+	}
+	if u.CategoryId != nil {
+		updates["category_id"] = *u.CategoryId
+	}
+	return updates
+}
+
+// RatingFilter membatasi recipe berdasarkan rating. Field nil diabaikan.
+type RatingFilter struct {
+	MinRating  *float64
+	MaxRating  *float64
+	MinReviews *int
+}
+
+func (f RatingFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.MinRating != nil {
+		db = db.Where("recipes.rating_average >= ?", *f.MinRating)
+	}
+	if f.MaxRating != nil {
+		db = db.Where("recipes.rating_average <= ?", *f.MaxRating)
+	}
+	if f.MinReviews != nil {
+		db = db.Where("recipes.rating_count >= ?", *f.MinReviews)
+	}
+	return db
+}
+
+// RecipeFilter berisi kriteria untuk FilterRecipesHandler. Field kosong/nil diabaikan.
+type RecipeFilter struct {
+	Category    string
+	MaxPrepTime *int
+	Servings    *int
+	AuthorId    *uint
+	RatingFilter
+	TagFilter
+}
+
+type RecipeRepository interface {
+	List(ctx context.Context, rating RatingFilter, opts ListOptions) ([]models.Recipe, int64, error)
+	FindByID(ctx context.Context, id uint) (models.Recipe, error)
+	// Create menyimpan recipe beserta baris bahan dan tag-nya dalam satu
+	// transaksi, lalu mengisi ulang recipe dengan semua relasinya.
+	Create(ctx context.Context, recipe *models.Recipe, lines []IngredientLine, tags []string) error
+	// Update menyimpan isi recipe sebelum diubah sebagai revisi baru, lalu
+	// mengubah recipe dalam transaksi yang sama.
+	Update(ctx context.Context, id uint, update RecipeUpdate) (models.Recipe, error)
+	Delete(ctx context.Context, id uint) error
+	// Search memakai full-text search pada Postgres dan LIKE pada driver lain.
+	Search(ctx context.Context, query string, rating RatingFilter, opts ListOptions) ([]RecipeSearchResult, int64, error)
+	Filter(ctx context.Context, filter RecipeFilter, opts ListOptions) ([]models.Recipe, int64, error)
+	// ListByCategory mengembalikan recipe yang berada di salah satu categoryIds.
+	ListByCategory(ctx context.Context, categoryIds []uint, opts ListOptions) ([]models.Recipe, int64, error)
+	// ListCookable mengurutkan recipe berdasarkan kelengkapan bahan yang
+	// tersedia: yang bisa langsung dibuat lebih dulu. Hanya page/page_size
+	// pada opts yang dipakai.
+	ListCookable(ctx context.Context, query CookableQuery, opts ListOptions) ([]CookableRecipe, int64, error)
+}
+
+// recipeSortable adalah field yang boleh dipakai pada parameter sort.
+var recipeSortable = map[string]string{
+	"id":           "recipes.id",
+	"title":        "recipes.title",
+	"created_at":   "recipes.created_at",
+	"prep_time":    "recipes.prep_time",
+	"cook_time":    "recipes.cook_time",
+	"rating":       "recipes.rating_average",
+	"rating_count": "recipes.rating_count",
+}
+
+type recipeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRepository(db *gorm.DB) RecipeRepository {
+	return &recipeRepository{db: db}
+}
+
+// preloadRecipe memuat semua relasi yang ditampilkan pada response recipe.
+func preloadRecipe(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Category").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tags.name")
+		}).
+		Preload("RecipeIngredients").
+		Preload("RecipeIngredients.Ingredient").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_images.id")
+		})
+}
+
+func (r *recipeRepository) List(ctx context.Context, rating RatingFilter, opts ListOptions) ([]models.Recipe, int64, error) {
+	var recipes []models.Recipe
+	db := rating.apply(r.db.WithContext(ctx).Model(&models.Recipe{}))
+	total, err := findPage(db, &recipes, opts, recipeSortable, preloadRecipe)
+	return recipes, total, err
+}
+
+func (r *recipeRepository) FindByID(ctx context.Context, id uint) (models.Recipe, error) {
+	var recipe models.Recipe
+	err := preloadRecipe(r.db.WithContext(ctx)).First(&recipe, id).Error
+	return recipe, translate(err)
+}
+
+func (r *recipeRepository) Create(ctx context.Context, recipe *models.Recipe, lines []IngredientLine, tags []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "RecipeIngredients", "Tags", "Images").Create(recipe).Error; err != nil {
+			return err
+		}
+		if err := replaceRecipeIngredients(tx, recipe.ID, lines); err != nil {
+			return err
+		}
+		return replaceRecipeTags(tx, recipe.ID, tags)
+	})
+	if err != nil {
+		return err
+	}
+	return preloadRecipe(r.db.WithContext(ctx)).First(recipe, recipe.ID).Error
+}
+
+func (r *recipeRepository) Update(ctx context.Context, id uint, update RecipeUpdate) (models.Recipe, error) {
+	var recipe models.Recipe
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&recipe, id).Error; err != nil {
+			return translate(err)
+		}
+		if err := createRevision(tx, recipe.ID, update.EditorId); err != nil {
+			return err
+		}
+		if columns := update.columns(); len(columns) > 0 {
+			if err := tx.Model(&recipe).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+		if update.Ingredients != nil {
+			if err := replaceRecipeIngredients(tx, recipe.ID, *update.Ingredients); err != nil {
+				return err
+			}
+		}
+		if update.Tags != nil {
+			return replaceRecipeTags(tx, recipe.ID, *update.Tags)
+		}
+		return nil
+	})
+	if err != nil {
+		return recipe, err
+	}
+
+	// Reload data untuk mendapatkan data terbaru
+	err = preloadRecipe(r.db.WithContext(ctx)).First(&recipe, id).Error
+	return recipe, translate(err)
+}
+
+// Delete melakukan soft delete recipe. Favorite dan entry collection yang
+// merujuk recipe ini ikut dihapus agar tidak menunjuk recipe yang hilang.
+func (r *recipeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("recipe_id = ?", id).Delete(&models.Favorite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("recipe_id = ?", id).Delete(&models.CollectionEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Recipe{}, id).Error
+	})
+}
+
+func (r *recipeRepository) Filter(ctx context.Context, filter RecipeFilter, opts ListOptions) ([]models.Recipe, int64, error) {
+	var recipes []models.Recipe
+
+	db := r.db.WithContext(ctx).Model(&models.Recipe{})
+
+	if filter.Category != "" {
+		db = db.Joins("JOIN categories ON categories.id = recipes.category_id").Where("categories.name = ?", filter.Category)
+	}
+	if filter.MaxPrepTime != nil {
+		db = db.Where("prep_time <= ?", *filter.MaxPrepTime)
+	}
+	if filter.Servings != nil {
+		db = db.Where("servings = ?", *filter.Servings)
+	}
+	if filter.AuthorId != nil {
+		db = db.Where("recipes.author_id = ?", *filter.AuthorId)
+	}
+	db = filter.RatingFilter.apply(db)
+	db = filter.TagFilter.apply(db)
+
+	total, err := findPage(db, &recipes, opts, recipeSortable, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Category").Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tags.name")
+		})
+	})
+	return recipes, total, err
+}
+
+func (r *recipeRepository) ListByCategory(ctx context.Context, categoryIds []uint, opts ListOptions) ([]models.Recipe, int64, error) {
+	var recipes []models.Recipe
+	db := r.db.WithContext(ctx).Model(&models.Recipe{}).Where("category_id IN ?", categoryIds)
+	total, err := findPage(db, &recipes, opts, recipeSortable, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Category")
+	})
+	return recipes, total, err
+}
+
+// replaceRecipeIngredients mengganti seluruh baris bahan milik recipe.
+// Harus dipanggil di dalam transaksi agar penggantian bersifat atomik.
+func replaceRecipeIngredients(tx *gorm.DB, recipeId uint, lines []IngredientLine) error {
+	if err := tx.Unscoped().Where("recipe_id = ?", recipeId).Delete(&models.RecipeIngredient{}).Error; err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		var ingredient models.Ingredient
+		name := strings.TrimSpace(line.Name)
+
+		switch {
+		case line.IngredientId != 0:
+			if err := tx.First(&ingredient, line.IngredientId).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: ingredient %d not found (line %d)", ErrInvalidIngredientLine, line.IngredientId, i+1)
+				}
+				return err
+			}
+		case name != "":
+			if err := tx.Where(models.Ingredient{Name: name}).FirstOrCreate(&ingredient).Error; err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: ingredient_id or name is required (line %d)", ErrInvalidIngredientLine, i+1)
+		}
+
+		recipeIngredient := models.RecipeIngredient{
+			RecipeId:     recipeId,
+			IngredientId: ingredient.ID,
+			Amount:       strings.TrimSpace(line.Amount),
+			Unit:         strings.TrimSpace(line.Unit),
+		}
+		if err := tx.Omit("Recipe", "Ingredient").Create(&recipeIngredient).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"go-rest-modul/models"
+	"strings"
+)
+
+// Jenis perubahan pada LineChange.
+const (
+	LineEqual   = "equal"
+	LineAdded   = "added"
+	LineRemoved = "removed"
+)
+
+// maxDiffCells membatasi ukuran tabel LCS (baris lama x baris baru) agar
+// teks yang sangat panjang tidak menghabiskan memori.
+const maxDiffCells = 1 << 22
+
+// FieldChange adalah satu field recipe yang nilainya berbeda.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// LineChange adalah satu baris pada diff per baris. FromLine dan ToLine
+// adalah nomor baris (mulai dari 1) di versi lama dan baru, kosong jika baris
+// tersebut tidak ada di versi itu.
+type LineChange struct {
+	Op       string `json:"op"`
+	Text     string `json:"text"`
+	FromLine int    `json:"from_line,omitempty"`
+	ToLine   int    `json:"to_line,omitempty"`
+}
+
+// RevisionDiff adalah perbedaan dua versi recipe. From dan To adalah nomor
+// revisi, 0 untuk isi recipe saat ini. Instructions dan Ingredients berisi
+// semua baris beserta perubahannya, atau kosong jika tidak ada yang berubah.
+type RevisionDiff struct {
+	From         int           `json:"from"`
+	To           int           `json:"to"`
+	Fields       []FieldChange `json:"fields"`
+	Instructions []LineChange  `json:"instructions"`
+	Ingredients  []LineChange  `json:"ingredients"`
+}
+
+// DiffRevisions membandingkan field, baris instructions dan baris bahan
+// dari revisi from ke revisi to.
+func DiffRevisions(from, to models.RecipeRevision) RevisionDiff {
+	diff := RevisionDiff{From: from.Number, To: to.Number, Fields: []FieldChange{}}
+	field := func(name string, a, b interface{}) {
+		if a != b {
+			diff.Fields = append(diff.Fields, FieldChange{Field: name, From: a, To: b})
+		}
+	}
+	field("title", from.Title, to.Title)
+	field("descriptions", from.Descriptions, to.Descriptions)
+	field("prep_time", from.PrepTime, to.PrepTime)
+	field("cook_time", from.CookTime, to.CookTime)
+	field("servings", from.Servings, to.Servings)
+	field("image_url", from.ImageURL, to.ImageURL)
+	field("category_id", from.CategoryId, to.CategoryId)
+	if from.Tags != to.Tags {
+		diff.Fields = append(diff.Fields, FieldChange{
+			Field: "tags",
+			From:  nonNil(splitRevisionTags(from.Tags)),
+			To:    nonNil(splitRevisionTags(to.Tags)),
+		})
+	}
+
+	diff.Instructions = diffLines(splitLines(from.Instructions), splitLines(to.Instructions))
+	diff.Ingredients = diffLines(ingredientTexts(from.Ingredients), ingredientTexts(to.Ingredients))
+	return diff
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// ingredientTexts menuliskan setiap baris bahan seperti "200 g Flour".
+func ingredientTexts(lines []models.RecipeRevisionIngredient) []string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		var parts []string
+		for _, part := range []string{line.Amount, line.Unit, line.Name} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		texts = append(texts, strings.Join(parts, " "))
+	}
+	return texts
+}
+
+// diffLines membandingkan baris a dan b dengan longest common subsequence.
+// Baris yang sama di awal dan akhir dilewati lebih dulu; jika sisanya terlalu
+// besar untuk tabel LCS, seluruh sisa a dianggap dihapus dan sisa b ditambahkan.
+func diffLines(a, b []string) []LineChange {
+	changes := []LineChange{}
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	if prefix == len(a) && prefix == len(b) {
+		return changes
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for i := 0; i < prefix; i++ {
+		changes = append(changes, LineChange{Op: LineEqual, Text: a[i], FromLine: i + 1, ToLine: i + 1})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(midA), len(midB)
+	removed := func(i int) {
+		changes = append(changes, LineChange{Op: LineRemoved, Text: midA[i], FromLine: prefix + i + 1})
+	}
+	added := func(j int) {
+		changes = append(changes, LineChange{Op: LineAdded, Text: midB[j], ToLine: prefix + j + 1})
+	}
+
+	i, j := 0, 0
+	if n*m <= maxDiffCells {
+		// lcs[i*(m+1)+j] adalah panjang LCS dari midA[i:] dan midB[j:]
+		lcs := make([]int32, (n+1)*(m+1))
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+				} else {
+					lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+				}
+			}
+		}
+		for i < n && j < m {
+			switch {
+			case midA[i] == midB[j]:
+				changes = append(changes, LineChange{Op: LineEqual, Text: midA[i], FromLine: prefix + i + 1, ToLine: prefix + j + 1})
+				i, j = i+1, j+1
+			case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+				removed(i)
+				i++
+			default:
+				added(j)
+				j++
+			}
+		}
+	}
+	for ; i < n; i++ {
+		removed(i)
+	}
+	for ; j < m; j++ {
+		added(j)
+	}
+
+	for k := 0; k < suffix; k++ {
+		changes = append(changes, LineChange{
+			Op:       LineEqual,
+			Text:     a[len(a)-suffix+k],
+			FromLine: len(a) - suffix + k + 1,
+			ToLine:   len(b) - suffix + k + 1,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-rest-modul/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CurrentRevision adalah nomor revisi untuk isi recipe saat ini.
+const CurrentRevision = 0
+
+type RecipeRevisionRepository interface {
+	// List mengembalikan revisi recipe tanpa baris bahannya.
+	List(ctx context.Context, recipeId uint, opts ListOptions) ([]models.RecipeRevision, int64, error)
+	// Find mengembalikan revisi beserta baris bahannya. CurrentRevision
+	// mengembalikan isi recipe saat ini dalam bentuk revisi.
+	Find(ctx context.Context, recipeId uint, number int) (models.RecipeRevision, error)
+	Diff(ctx context.Context, recipeId uint, from, to int) (RevisionDiff, error)
+	// Restore mengembalikan isi recipe ke revisi number lewat Update biasa,
+	// sehingga isi sebelum restore tersimpan sebagai revisi baru. ImageURL
+	// tidak ikut dipulihkan karena file gambar lama mungkin sudah dihapus.
+	// ErrRevisionCategoryMissing dikembalikan jika category revisi sudah
+	// dihapus dan berbeda dari category recipe saat ini.
+	Restore(ctx context.Context, recipeId uint, number int, editorId *uint) (models.Recipe, error)
+}
+
+// revisionSortable adalah field yang boleh dipakai pada parameter sort.
+var revisionSortable = map[string]string{
+	"id":         "recipe_revisions.id",
+	"number":     "recipe_revisions.number",
+	"created_at": "recipe_revisions.created_at",
+}
+
+type recipeRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRevisionRepository(db *gorm.DB) RecipeRevisionRepository {
+	return &recipeRevisionRepository{db: db}
+}
+
+func (r *recipeRevisionRepository) List(ctx context.Context, recipeId uint, opts ListOptions) ([]models.RecipeRevision, int64, error) {
+	var revisions []models.RecipeRevision
+	db := r.db.WithContext(ctx).Model(&models.RecipeRevision{}).Where("recipe_revisions.recipe_id = ?", recipeId)
+	total, err := findPage(db, &revisions, opts, revisionSortable, nil)
+	return revisions, total, err
+}
+
+func (r *recipeRevisionRepository) Find(ctx context.Context, recipeId uint, number int) (models.RecipeRevision, error) {
+	if number == CurrentRevision {
+		recipe, err := loadRevisionSource(r.db.WithContext(ctx), recipeId)
+		if err != nil {
+			return models.RecipeRevision{}, err
+		}
+		return revisionOf(recipe), nil
+	}
+
+	var revision models.RecipeRevision
+	err := r.db.WithContext(ctx).
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_revision_ingredients.position")
+		}).
+		Where("recipe_id = ? AND number = ?", recipeId, number).
+		Take(&revision).Error
+	return revision, translate(err)
+}
+
+func (r *recipeRevisionRepository) Diff(ctx context.Context, recipeId uint, from, to int) (RevisionDiff, error) {
+	fromRevision, err := r.Find(ctx, recipeId, from)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	toRevision, err := r.Find(ctx, recipeId, to)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	return DiffRevisions(fromRevision, toRevision), nil
+}
+
+func (r *recipeRevisionRepository) Restore(ctx context.Context, recipeId uint, number int, editorId *uint) (models.Recipe, error) {
+	revision, err := r.Find(ctx, recipeId, number)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	// Bahan yang sudah dihapus dicari lagi lewat namanya
+	ids := make([]uint, 0, len(revision.Ingredients))
+	for _, ingredient := range revision.Ingredients {
+		ids = append(ids, ingredient.IngredientId)
+	}
+	var existing []uint
+	if len(ids) > 0 {
+		err := r.db.WithContext(ctx).Model(&models.Ingredient{}).Where("id IN ?", ids).Pluck("id", &existing).Error
+		if err != nil {
+			return models.Recipe{}, err
+		}
+	}
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	lines := make([]IngredientLine, 0, len(revision.Ingredients))
+	for _, ingredient := range revision.Ingredients {
+		line := IngredientLine{Name: ingredient.Name, Amount: ingredient.Amount, Unit: ingredient.Unit}
+		if found[ingredient.IngredientId] {
+			line.IngredientId = ingredient.IngredientId
+		}
+		lines = append(lines, line)
+	}
+	tags := splitRevisionTags(revision.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	update := RecipeUpdate{
+		Title:        &revision.Title,
+		Descriptions: &revision.Descriptions,
+		Instructions: &revision.Instructions,
+		PrepTime:     &revision.PrepTime,
+		CookTime:     &revision.CookTime,
+		Servings:     &revision.Servings,
+		CategoryId:   &revision.CategoryId,
+		Ingredients:  &lines,
+		Tags:         &tags,
+		EditorId:     editorId,
+	}
+
+	var restored models.Recipe
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Category tidak bisa dibuat ulang seperti bahan, jadi restore ditolak
+		// jika category revisi sudah dihapus. Baris category dikunci sampai
+		// commit agar tidak terhapus di tengah restore.
+		var current models.Recipe
+		if err := tx.Select("id", "category_id").First(&current, recipeId).Error; err != nil {
+			return translate(err)
+		}
+		if revision.CategoryId != current.CategoryId {
+			var category models.Category
+			result := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+				Select("id").Where("id = ?", revision.CategoryId).Limit(1).Find(&category)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: category %d", ErrRevisionCategoryMissing, revision.CategoryId)
+			}
+		}
+
+		var err error
+		restored, err = (&recipeRepository{db: tx}).Update(ctx, recipeId, update)
+		return err
+	})
+	return restored, err
+}
+
+// loadRevisionSource memuat recipe beserta baris bahan dan tag yang disalin
+// ke revisi. Bahan yang sudah dihapus tetap dimuat agar namanya tersalin.
+func loadRevisionSource(db *gorm.DB, recipeId uint) (models.Recipe, error) {
+	var recipe models.Recipe
+	err := db.
+		Preload("RecipeIngredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_ingredients.id")
+		}).
+		Preload("RecipeIngredients.Ingredient", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tags.name")
+		}).
+		First(&recipe, recipeId).Error
+	return recipe, translate(err)
+}
+
+// revisionOf menyalin isi recipe ke revisi tanpa nomor.
+func revisionOf(recipe models.Recipe) models.RecipeRevision {
+	tags := make([]string, 0, len(recipe.Tags))
+	for _, tag := range recipe.Tags {
+		tags = append(tags, tag.Name)
+	}
+	revision := models.RecipeRevision{
+		RecipeId:     recipe.ID,
+		Title:        recipe.Title,
+		Descriptions: recipe.Descriptions,
+		Instructions: recipe.Instructions,
+		PrepTime:     recipe.PrepTime,
+		CookTime:     recipe.CookTime,
+		Servings:     recipe.Servings,
+		ImageURL:     recipe.ImageURL,
+		CategoryId:   recipe.CategoryId,
+		Tags:         strings.Join(tags, "\n"),
+	}
+	for i, line := range recipe.RecipeIngredients {
+		revision.Ingredients = append(revision.Ingredients, models.RecipeRevisionIngredient{
+			Position:     i + 1,
+			IngredientId: line.IngredientId,
+			Name:         line.Ingredient.Name,
+			Amount:       line.Amount,
+			Unit:         line.Unit,
+		})
+	}
+	return revision
+}
+
+// createRevision menyimpan isi recipe saat ini sebagai revisi berikutnya.
+// Harus dipanggil di dalam transaksi yang sama dengan perubahan recipe.
+func createRevision(tx *gorm.DB, recipeId uint, editorId *uint) error {
+	recipe, err := loadRevisionSource(tx, recipeId)
+	if err != nil {
+		return err
+	}
+
+	var last int
+	err = tx.Model(&models.RecipeRevision{}).
+		Where("recipe_id = ?", recipeId).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	revision := revisionOf(recipe)
+	revision.Number = last + 1
+	revision.EditorId = editorId
+	return tx.Create(&revision).Error
+}
+
+func splitRevisionTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, "\n")
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"go-rest-modul/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// RecipeSearchResult adalah recipe hasil pencarian beserta skor relevansi
+// dan potongan teks yang menyorot kata yang cocok. Rank dan Highlights hanya
+// terisi pada Postgres.
+type RecipeSearchResult struct {
+	models.Recipe
+	Rank       float64           `json:"rank"`
+	Highlights *SearchHighlights `json:"highlights,omitempty"`
+}
+
+type SearchHighlights struct {
+	Title        string `json:"title"`
+	Descriptions string `json:"descriptions"`
+	Instructions string `json:"instructions"`
+}
+
+// headlineOptions dipakai ts_headline untuk membentuk snippet.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter= ... "
+
+// searchRankColumn adalah ekspresi skor relevansi, bergantung pada
+// CROSS JOIN websearch_to_tsquery(...) AS query di query dasar.
+const searchRankColumn = "ts_rank(recipes.search_vector, query)"
+
+func (r *recipeRepository) Search(ctx context.Context, query string, rating RatingFilter, opts ListOptions) ([]RecipeSearchResult, int64, error) {
+	query = strings.TrimSpace(query)
+	if r.db.Dialector.Name() == "postgres" && query != "" {
+		return r.fullTextSearch(ctx, query, rating, opts)
+	}
+	return r.likeSearch(ctx, query, rating, opts)
+}
+
+func (r *recipeRepository) fullTextSearch(ctx context.Context, query string, rating RatingFilter, opts ListOptions) ([]RecipeSearchResult, int64, error) {
+	var recipes []models.Recipe
+
+	sortable := map[string]string{"rank": searchRankColumn}
+	for field, column := range recipeSortable {
+		sortable[field] = column
+	}
+	// Default hasil pencarian diurutkan dari yang paling relevan
+	if opts.Sort == "" {
+		opts.Sort = "-rank"
+	}
+
+	db := r.db.WithContext(ctx).Model(&models.Recipe{}).
+		Joins("CROSS JOIN websearch_to_tsquery('simple', ?) AS query", query).
+		Where("recipes.search_vector @@ query")
+	db = rating.apply(db)
+	total, err := findPage(db, &recipes, opts, sortable, func(db *gorm.DB) *gorm.DB {
+		return preloadRecipe(db).Preload("RecipeIngredients.Recipe")
+	})
+	if err != nil || len(recipes) == 0 {
+		return nil, total, err
+	}
+
+	// Skor dan snippet hanya dihitung untuk baris di halaman ini
+	ids := make([]uint, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	var extras []struct {
+		ID                    uint
+		Rank                  float64
+		TitleHighlight        string
+		DescriptionsHighlight string
+		InstructionsHighlight string
+	}
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT recipes.id,
+			`+searchRankColumn+` AS rank,
+			ts_headline('simple', coalesce(recipes.title, ''), query, @options) AS title_highlight,
+			ts_headline('simple', coalesce(recipes.descriptions, ''), query, @options) AS descriptions_highlight,
+			ts_headline('simple', coalesce(recipes.instructions, ''), query, @options) AS instructions_highlight
+		FROM recipes
+		CROSS JOIN websearch_to_tsquery('simple', @query) AS query
+		WHERE recipes.id IN @ids`,
+		map[string]interface{}{"options": headlineOptions, "query": query, "ids": ids},
+	).Scan(&extras).Error
+	if err != nil {
+		return nil, total, err
+	}
+
+	results := make([]RecipeSearchResult, len(recipes))
+	for i, recipe := range recipes {
+		results[i].Recipe = recipe
+		for _, extra := range extras {
+			if extra.ID == recipe.ID {
+				results[i].Rank = extra.Rank
+				results[i].Highlights = &SearchHighlights{
+					Title:        extra.TitleHighlight,
+					Descriptions: extra.DescriptionsHighlight,
+					Instructions: extra.InstructionsHighlight,
+				}
+				break
+			}
+		}
+	}
+	return results, total, nil
+}
+
+// likeEscaper meloloskan karakter wildcard LIKE agar query dicari apa adanya.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// likeSearch adalah pencarian sederhana untuk driver tanpa full-text search.
+// Seperti full-text search Postgres, pencarian tidak membedakan huruf besar
+// dan kecil; kedua sisi di-LOWER karena SQLite memakai case_sensitive_like.
+func (r *recipeRepository) likeSearch(ctx context.Context, query string, rating RatingFilter, opts ListOptions) ([]RecipeSearchResult, int64, error) {
+	var recipes []models.Recipe
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	db := r.db.WithContext(ctx).Model(&models.Recipe{}).
+		Where(`LOWER(title) LIKE @pattern ESCAPE '\'
+			OR LOWER(descriptions) LIKE @pattern ESCAPE '\'
+			OR LOWER(instructions) LIKE @pattern ESCAPE '\'`,
+			map[string]interface{}{"pattern": pattern})
+	db = rating.apply(db)
+	total, err := findPage(db, &recipes, opts, recipeSortable, func(db *gorm.DB) *gorm.DB {
+		return preloadRecipe(db).Preload("RecipeIngredients.Recipe")
+	})
+	if err != nil {
+		return nil, total, err
+	}
+
+	results := make([]RecipeSearchResult, len(recipes))
+	for i, recipe := range recipes {
+		results[i].Recipe = recipe
+	}
+	return results, total, nil
+}
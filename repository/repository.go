@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound dikembalikan jika data yang dicari tidak ada.
+	ErrNotFound = errors.New("record not found")
+	// ErrInvalidIngredientLine dikembalikan jika baris bahan pada recipe tidak valid.
+	ErrInvalidIngredientLine = errors.New("invalid ingredient line")
+	// ErrRevisionCategoryMissing dikembalikan jika category pada revisi
+	// yang akan dipulihkan sudah dihapus.
+	ErrRevisionCategoryMissing = errors.New("revision category no longer exists")
+)
+
+// translate menyeragamkan error gorm menjadi error milik package ini, supaya
+// handler tidak bergantung pada gorm.
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-rest-modul/models"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// ErrReviewExists dikembalikan jika user sudah pernah mereview recipe.
+var ErrReviewExists = errors.New("review already exists")
+
+// ReviewResult adalah review beserta username penulisnya.
+type ReviewResult struct {
+	models.Review
+	Username string `json:"username"`
+}
+
+// ReviewUpdate berisi field review yang akan diubah. Field nil tidak disentuh.
+type ReviewUpdate struct {
+	Rating *int
+	Body   *string
+}
+
+type ReviewRepository interface {
+	ListByRecipe(ctx context.Context, recipeId uint, opts ListOptions) ([]ReviewResult, int64, error)
+	FindByID(ctx context.Context, id uint) (ReviewResult, error)
+	// Create, Update dan Delete juga menghitung ulang rating recipe dalam
+	// transaksi yang sama.
+	Create(ctx context.Context, review *models.Review) error
+	Update(ctx context.Context, id uint, update ReviewUpdate) (ReviewResult, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// reviewSortable adalah field yang boleh dipakai pada parameter sort.
+var reviewSortable = map[string]string{
+	"id":         "reviews.id",
+	"rating":     "reviews.rating",
+	"created_at": "reviews.created_at",
+	"updated_at": "reviews.updated_at",
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// reviewQuery memilih kolom review beserta username penulis.
+func reviewQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Review{}).
+		Select("reviews.*, users.username").
+		Joins("LEFT JOIN users ON users.id = reviews.user_id")
+}
+
+func (r *reviewRepository) ListByRecipe(ctx context.Context, recipeId uint, opts ListOptions) ([]ReviewResult, int64, error) {
+	var reviews []ReviewResult
+	db := reviewQuery(r.db.WithContext(ctx)).Where("reviews.recipe_id = ?", recipeId)
+	total, err := findPage(db, &reviews, opts, reviewSortable, nil)
+	return reviews, total, err
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id uint) (ReviewResult, error) {
+	var review ReviewResult
+	err := reviewQuery(r.db.WithContext(ctx)).Where("reviews.id = ?", id).Take(&review).Error
+	return review, translate(err)
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *models.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Recipe{}, review.RecipeId).Error; err != nil {
+			return translate(err)
+		}
+		var count int64
+		err := tx.Model(&models.Review{}).
+			Where("recipe_id = ? AND user_id = ?", review.RecipeId, review.UserId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrReviewExists
+		}
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		return refreshRecipeRating(tx, review.RecipeId)
+	})
+}
+
+func (r *reviewRepository) Update(ctx context.Context, id uint, update ReviewUpdate) (ReviewResult, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.First(&review, id).Error; err != nil {
+			return translate(err)
+		}
+		columns := make(map[string]interface{})
+		if update.Rating != nil {
+			columns["rating"] = *update.Rating
+		}
+		if update.Body != nil {
+			columns["body"] = *update.Body
+		}
+		if len(columns) == 0 {
+			return nil
+		}
+		if err := tx.Model(&review).Updates(columns).Error; err != nil {
+			return err
+		}
+		return refreshRecipeRating(tx, review.RecipeId)
+	})
+	if err != nil {
+		return ReviewResult{}, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.First(&review, id).Error; err != nil {
+			return translate(err)
+		}
+		// Dihapus permanen agar user bisa mereview lagi recipe yang sama
+		if err := tx.Unscoped().Delete(&review).Error; err != nil {
+			return err
+		}
+		return refreshRecipeRating(tx, review.RecipeId)
+	})
+}
+
+// refreshRecipeRating menghitung ulang rata-rata dan jumlah review recipe.
+func refreshRecipeRating(tx *gorm.DB, recipeId uint) error {
+	var stats struct {
+		Average float64
+		Count   int
+	}
+	err := tx.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("recipe_id = ?", recipeId).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+	// Dibulatkan 2 desimal supaya hasilnya sama di semua driver
+	average := math.Round(stats.Average*100) / 100
+	return tx.Model(&models.Recipe{}).Unscoped().
+		Where("id = ?", recipeId).
+		UpdateColumns(map[string]interface{}{"rating_average": average, "rating_count": stats.Count}).Error
+}
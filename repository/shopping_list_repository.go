@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"go-rest-modul/units"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidRecipeSelection dikembalikan jika recipe yang dipilih untuk
+// daftar belanja tidak ada atau jumlah porsinya tidak valid.
+var ErrInvalidRecipeSelection = errors.New("invalid recipe selection")
+
+// RecipeSelection adalah recipe yang dimasukkan ke daftar belanja. Servings 0
+// berarti memakai jumlah porsi recipe itu sendiri.
+type RecipeSelection struct {
+	RecipeId uint `json:"recipe_id"`
+	Servings int  `json:"servings"`
+}
+
+// NewShoppingList berisi data untuk membuat daftar belanja.
+type NewShoppingList struct {
+	UserId  uint
+	Name    string
+	Recipes []RecipeSelection
+	// System adalah sistem satuan hasil; kosong berarti mengikuti resep
+	System units.System
+}
+
+// ShoppingListRepository hanya mengakses daftar belanja milik userId; daftar
+// milik user lain dianggap tidak ada (ErrNotFound).
+type ShoppingListRepository interface {
+	List(ctx context.Context, userId uint, opts ListOptions) ([]models.ShoppingList, int64, error)
+	FindByID(ctx context.Context, userId, id uint) (models.ShoppingList, error)
+	// Create menyusun item dari baris bahan recipe yang dipilih, lalu
+	// menyimpan daftar belanja beserta itemnya dalam satu transaksi.
+	Create(ctx context.Context, list NewShoppingList) (models.ShoppingList, error)
+	// SetItemChecked menandai item sudah/belum dibeli.
+	SetItemChecked(ctx context.Context, userId, listId, itemId uint, checked bool) (models.ShoppingListItem, error)
+	Delete(ctx context.Context, userId, id uint) error
+}
+
+var shoppingListSortable = map[string]string{
+	"id":         "shopping_lists.id",
+	"name":       "shopping_lists.name",
+	"created_at": "shopping_lists.created_at",
+}
+
+type shoppingListRepository struct {
+	db *gorm.DB
+}
+
+func NewShoppingListRepository(db *gorm.DB) ShoppingListRepository {
+	return &shoppingListRepository{db: db}
+}
+
+func preloadShoppingList(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Recipes").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shopping_list_items.id")
+		}).
+		Preload("Items.Ingredient", func(db *gorm.DB) *gorm.DB {
+			// Bahan yang sudah dihapus tetap ditampilkan namanya
+			return db.Unscoped()
+		})
+}
+
+// findOwnedShoppingList memastikan daftar belanja ada dan dimiliki userId.
+func findOwnedShoppingList(tx *gorm.DB, userId, id uint) (models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := tx.Where("user_id = ?", userId).First(&list, id).Error
+	return list, translate(err)
+}
+
+func (r *shoppingListRepository) List(ctx context.Context, userId uint, opts ListOptions) ([]models.ShoppingList, int64, error) {
+	var lists []models.ShoppingList
+	db := r.db.WithContext(ctx).Model(&models.ShoppingList{}).Where("shopping_lists.user_id = ?", userId)
+	total, err := findPage(db, &lists, opts, shoppingListSortable, preloadShoppingList)
+	return lists, total, err
+}
+
+func (r *shoppingListRepository) FindByID(ctx context.Context, userId, id uint) (models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := preloadShoppingList(r.db.WithContext(ctx)).Where("user_id = ?", userId).First(&list, id).Error
+	return list, translate(err)
+}
+
+func (r *shoppingListRepository) Create(ctx context.Context, input NewShoppingList) (models.ShoppingList, error) {
+	list := models.ShoppingList{UserId: input.UserId, Name: input.Name}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		selected, items, err := buildShoppingItems(tx, input.Recipes, input.System)
+		if err != nil {
+			return err
+		}
+		list.Recipes = selected
+		list.Items = items
+		return tx.Omit("Recipes.Recipe", "Items.Ingredient").Create(&list).Error
+	})
+	if err != nil {
+		return list, err
+	}
+	return r.FindByID(ctx, list.UserId, list.ID)
+}
+
+// buildShoppingItems memuat recipe yang dipilih dan menggabungkan baris
+// bahannya menjadi item daftar belanja.
+func buildShoppingItems(db *gorm.DB, selections []RecipeSelection, system units.System) ([]models.ShoppingListRecipe, []models.ShoppingListItem, error) {
+	var (
+		selected []models.ShoppingListRecipe
+		lines    []scaledLine
+	)
+	for _, selection := range selections {
+		if selection.Servings < 0 {
+			return nil, nil, fmt.Errorf("%w: servings cannot be negative", ErrInvalidRecipeSelection)
+		}
+
+		var recipe models.Recipe
+		err := db.Preload("RecipeIngredients").Preload("RecipeIngredients.Ingredient").First(&recipe, selection.RecipeId).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("%w: recipe %d not found", ErrInvalidRecipeSelection, selection.RecipeId)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Recipe tanpa jumlah porsi tidak bisa diskalakan, pakai apa adanya
+		servings, factor := selection.Servings, 1.0
+		if servings == 0 {
+			servings = recipe.Servings
+		} else if recipe.Servings > 0 {
+			factor = float64(servings) / float64(recipe.Servings)
+		}
+
+		selected = append(selected, models.ShoppingListRecipe{RecipeId: recipe.ID, Servings: servings})
+		for _, line := range recipe.RecipeIngredients {
+			lines = append(lines, scaledLine{line: line, factor: factor})
+		}
+	}
+	return selected, mergeShoppingLines(lines, system), nil
+}
+
+func (r *shoppingListRepository) SetItemChecked(ctx context.Context, userId, listId, itemId uint, checked bool) (models.ShoppingListItem, error) {
+	var item models.ShoppingListItem
+	db := r.db.WithContext(ctx)
+	if _, err := findOwnedShoppingList(db, userId, listId); err != nil {
+		return item, err
+	}
+	if err := db.Where("shopping_list_id = ?", listId).First(&item, itemId).Error; err != nil {
+		return item, translate(err)
+	}
+	if err := db.Model(&item).Update("checked", checked).Error; err != nil {
+		return item, err
+	}
+	err := db.Preload("Ingredient", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).First(&item, itemId).Error
+	return item, translate(err)
+}
+
+// Delete menghapus daftar belanja beserta item dan catatan recipe-nya.
+func (r *shoppingListRepository) Delete(ctx context.Context, userId, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedShoppingList(tx, userId, id); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("shopping_list_id = ?", id).Delete(&models.ShoppingListItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("shopping_list_id = ?", id).Delete(&models.ShoppingListRecipe{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Delete(&models.ShoppingList{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"go-rest-modul/models"
+	"go-rest-modul/units"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scaledLine adalah baris bahan recipe beserta faktor pengali porsinya.
+type scaledLine struct {
+	line   models.RecipeIngredient
+	factor float64
+}
+
+// shoppingGroup menampung jumlah satu bahan yang bisa dijumlahkan.
+type shoppingGroup struct {
+	ingredient models.Ingredient
+	// unit diisi untuk satuan yang dikenal; jumlah disimpan dalam satuan dasar
+	unit   *units.Unit
+	system units.System
+	// rawUnit dipakai untuk satuan yang tidak dikenal, misal "siung"
+	rawUnit string
+	amount  units.Amount
+	// text dipakai jika amount tidak bisa dibaca, misal "secukupnya"
+	text   string
+	isText bool
+}
+
+// mergeShoppingLines menggabungkan baris bahan yang sama dari beberapa recipe.
+// Satuan yang sedimensi dijumlahkan lewat satuan dasarnya, volume digabung ke
+// massa bila density bahan diketahui, lalu hasilnya ditulis dengan satuan
+// yang paling mudah dibaca. Jika system kosong, sistem satuan baris pertama
+// yang dipakai.
+func mergeShoppingLines(lines []scaledLine, system units.System) []models.ShoppingListItem {
+	var groups []*shoppingGroup
+	index := make(map[string]*shoppingGroup)
+
+	add := func(key string, group *shoppingGroup, amount units.Amount) {
+		if existing, ok := index[key]; ok {
+			existing.amount = existing.amount.Add(amount)
+			return
+		}
+		group.amount = amount
+		index[key] = group
+		groups = append(groups, group)
+	}
+
+	for _, scaled := range lines {
+		line := scaled.line
+		id := strconv.FormatUint(uint64(line.IngredientId), 10)
+
+		amount, err := units.ParseAmount(line.Amount)
+		if err != nil {
+			// Tidak bisa dijumlahkan, tampilkan apa adanya
+			groups = append(groups, &shoppingGroup{
+				ingredient: line.Ingredient,
+				rawUnit:    line.Unit,
+				text:       strings.TrimSpace(line.Amount),
+				isText:     true,
+			})
+			continue
+		}
+		amount = amount.Scale(scaled.factor)
+
+		unit, err := units.Lookup(line.Unit)
+		if err != nil {
+			rawUnit := strings.TrimSpace(line.Unit)
+			add(id+"|raw|"+strings.ToLower(rawUnit), &shoppingGroup{
+				ingredient: line.Ingredient,
+				rawUnit:    rawUnit,
+			}, amount)
+			continue
+		}
+
+		quantity := units.Quantity{Amount: amount, Unit: unit}
+		add(id+"|"+string(unit.Dimension), &shoppingGroup{
+			ingredient: line.Ingredient,
+			unit:       &unit,
+			system:     unit.System,
+		}, quantity.Base())
+	}
+
+	// Gabungkan volume ke massa jika bahan yang sama ditulis dengan keduanya
+	merged := groups[:0]
+	for _, group := range groups {
+		if group.unit != nil && group.unit.Dimension == units.Volume && group.ingredient.Density > 0 {
+			id := strconv.FormatUint(uint64(group.ingredient.ID), 10)
+			if mass, ok := index[id+"|"+string(units.Mass)]; ok {
+				mass.amount = mass.amount.Add(group.amount.Scale(group.ingredient.Density))
+				continue
+			}
+		}
+		merged = append(merged, group)
+	}
+
+	items := make([]models.ShoppingListItem, 0, len(merged))
+	for _, group := range merged {
+		item := models.ShoppingListItem{
+			IngredientId: group.ingredient.ID,
+			Ingredient:   group.ingredient,
+			Unit:         group.rawUnit,
+		}
+		switch {
+		case group.isText:
+			item.Amount = group.text
+		case group.unit == nil:
+			item.Amount = units.FormatAmount(group.amount, units.Neutral)
+		default:
+			target := system
+			if target == "" {
+				target = group.system
+			}
+			base := units.Quantity{Amount: group.amount, Unit: baseUnit(group.unit.Dimension)}
+			item.Amount, item.Unit = base.InSystem(target).Format()
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Ingredient.Name) < strings.ToLower(items[j].Ingredient.Name)
+	})
+	return items
+}
+
+// baseUnit adalah satuan dengan Factor 1 untuk setiap dimensi.
+func baseUnit(dimension units.Dimension) units.Unit {
+	switch dimension {
+	case units.Volume:
+		return units.Milliliter
+	case units.Mass:
+		return units.Gram
+	default:
+		return units.Piece
+	}
+}
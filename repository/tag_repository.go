@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-rest-modul/models"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// MaxTagLength adalah panjang maksimum nama tag (dalam karakter).
+const MaxTagLength = 50
+
+var (
+	// ErrInvalidTag dikembalikan jika nama tag kosong atau terlalu panjang.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrTagExists dikembalikan jika nama tag sudah dipakai tag lain.
+	ErrTagExists = errors.New("tag already exists")
+)
+
+// NormalizeTag merapikan nama tag: huruf kecil dan spasi tunggal, sehingga
+// "Gluten Free" dan " gluten  free" dianggap tag yang sama.
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// validTagName menormalkan lalu memeriksa nama tag.
+func validTagName(name string) (string, error) {
+	name = NormalizeTag(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name cannot be empty", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, name, MaxTagLength)
+	}
+	return name, nil
+}
+
+// normalizeTags menormalkan daftar nama tag dan membuang duplikat tanpa
+// mengubah urutan. Nama kosong diabaikan.
+func normalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var result []string
+	for _, name := range names {
+		name = NormalizeTag(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+type TagRepository interface {
+	List(ctx context.Context, opts ListOptions) ([]models.Tag, int64, error)
+	FindByID(ctx context.Context, id uint) (models.Tag, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	// Rename mengganti nama tag; recipe yang memakai tag ini ikut berubah.
+	Rename(ctx context.Context, id uint, name string) (models.Tag, error)
+	// Delete menghapus tag dan melepasnya dari semua recipe.
+	Delete(ctx context.Context, id uint) error
+}
+
+var tagSortable = map[string]string{
+	"id":         "tags.id",
+	"name":       "tags.name",
+	"created_at": "tags.created_at",
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) List(ctx context.Context, opts ListOptions) ([]models.Tag, int64, error) {
+	var tags []models.Tag
+	db := r.db.WithContext(ctx).Model(&models.Tag{})
+	total, err := findPage(db, &tags, opts, tagSortable, nil)
+	return tags, total, err
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (models.Tag, error) {
+	var tag models.Tag
+	err := r.db.WithContext(ctx).First(&tag, id).Error
+	return tag, translate(err)
+}
+
+// tagNameTaken bernilai true jika nama sudah dipakai tag selain exceptId.
+func tagNameTaken(tx *gorm.DB, name string, exceptId uint) (bool, error) {
+	var count int64
+	err := tx.Model(&models.Tag{}).Where("name = ? AND id <> ?", name, exceptId).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	name, err := validTagName(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taken, err := tagNameTaken(tx, name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrTagExists
+		}
+		return tx.Create(tag).Error
+	})
+}
+
+func (r *tagRepository) Rename(ctx context.Context, id uint, name string) (models.Tag, error) {
+	var tag models.Tag
+	name, err := validTagName(name)
+	if err != nil {
+		return tag, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&tag, id).Error; err != nil {
+			return translate(err)
+		}
+		taken, err := tagNameTaken(tx, name, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrTagExists
+		}
+		return tx.Model(&tag).Update("name", name).Error
+	})
+	return tag, err
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM recipe_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		// Dihapus permanen agar namanya bisa dipakai lagi
+		result := tx.Unscoped().Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// replaceRecipeTags mengganti semua tag recipe. Tag yang belum ada dibuat.
+func replaceRecipeTags(tx *gorm.DB, recipeId uint, names []string) error {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range normalizeTags(names) {
+		name, err := validTagName(name)
+		if err != nil {
+			return err
+		}
+		var tag models.Tag
+		if err := tx.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+
+	recipe := models.Recipe{}
+	recipe.ID = recipeId
+	return tx.Model(&recipe).Omit("Tags.*").Association("Tags").Replace(tags)
+}
+
+// TagFilter membatasi recipe berdasarkan tag. Semua nama tag dinormalkan.
+type TagFilter struct {
+	// AllOf: recipe harus punya semua tag ini
+	AllOf []string
+	// AnyOf: recipe harus punya minimal satu tag ini
+	AnyOf []string
+	// NoneOf: recipe tidak boleh punya satu pun tag ini
+	NoneOf []string
+}
+
+// recipesWithTags adalah subquery id recipe yang punya salah satu tag names.
+func recipesWithTags(db *gorm.DB, names []string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("recipe_tags").
+		Select("recipe_tags.recipe_id").
+		Joins("JOIN tags ON tags.id = recipe_tags.tag_id").
+		Where("tags.name IN ?", names)
+}
+
+func (f TagFilter) apply(db *gorm.DB) *gorm.DB {
+	if names := normalizeTags(f.AllOf); len(names) > 0 {
+		db = db.Where("recipes.id IN (?)", recipesWithTags(db, names).
+			Group("recipe_tags.recipe_id").
+			Having("COUNT(DISTINCT tags.id) = ?", len(names)))
+	}
+	if names := normalizeTags(f.AnyOf); len(names) > 0 {
+		db = db.Where("recipes.id IN (?)", recipesWithTags(db, names))
+	}
+	if names := normalizeTags(f.NoneOf); len(names) > 0 {
+		db = db.Where("recipes.id NOT IN (?)", recipesWithTags(db, names))
+	}
+	return db
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-rest-modul/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUserExists dikembalikan jika username atau email sudah dipakai.
+	ErrUserExists = errors.New("user already exists")
+	// ErrSessionRevoked dikembalikan jika refresh token sudah dicabut,
+	// sudah pernah dipakai, atau kedaluwarsa.
+	ErrSessionRevoked = errors.New("session revoked")
+)
+
+type UserRepository interface {
+	List(ctx context.Context, opts ListOptions) ([]models.User, int64, error)
+	FindByID(ctx context.Context, id uint) (models.User, error)
+	// FindByLogin mencari user berdasarkan username atau email.
+	FindByLogin(ctx context.Context, login string) (models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	UpdateRole(ctx context.Context, id uint, role string) (models.User, error)
+	// Delete menghapus user (soft delete) dan mencabut semua session-nya.
+	Delete(ctx context.Context, id uint) error
+
+	CreateSession(ctx context.Context, session *models.Session) error
+	// RotateSession mencabut session tokenId lalu menyimpan next dalam satu
+	// transaksi. Setiap refresh token hanya bisa dipakai sekali.
+	RotateSession(ctx context.Context, tokenId string, next *models.Session) error
+	RevokeSession(ctx context.Context, tokenId string) error
+	// RevokeUserSessions mencabut semua session milik user.
+	RevokeUserSessions(ctx context.Context, userId uint) error
+}
+
+// userSortable adalah field yang boleh dipakai pada parameter sort.
+var userSortable = map[string]string{
+	"id":         "users.id",
+	"username":   "users.username",
+	"role":       "users.role",
+	"created_at": "users.created_at",
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) List(ctx context.Context, opts ListOptions) ([]models.User, int64, error) {
+	var users []models.User
+	db := r.db.WithContext(ctx).Model(&models.User{})
+	total, err := findPage(db, &users, opts, userSortable, nil)
+	return users, total, err
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	return user, translate(err)
+}
+
+func (r *userRepository) FindByLogin(ctx context.Context, login string) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Where("username = ? OR email = ?", login, strings.ToLower(login)).
+		First(&user).Error
+	return user, translate(err)
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	user.Email = strings.ToLower(user.Email)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Dicek dulu agar error-nya sama untuk Postgres dan SQLite; unique
+		// index tetap menjaga jika ada dua registrasi bersamaan
+		var count int64
+		err := tx.Model(&models.User{}).Unscoped().
+			Where("username = ? OR email = ?", user.Username, user.Email).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUserExists
+		}
+		return tx.Create(user).Error
+	})
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, id).Error; err != nil {
+			return translate(err)
+		}
+		return tx.Model(&user).Update("role", role).Error
+	})
+	return user, err
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+func (r *userRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *userRepository) RotateSession(ctx context.Context, tokenId string, next *models.Session) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Session{}).
+			Where("token_id = ? AND revoked_at IS NULL AND expires_at > ?", tokenId, now).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSessionRevoked
+		}
+		return tx.Create(next).Error
+	})
+}
+
+func (r *userRepository) RevokeSession(ctx context.Context, tokenId string) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *userRepository) RevokeUserSessions(ctx context.Context, userId uint) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", time.Now()).Error
+}
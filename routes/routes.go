@@ -2,35 +2,231 @@ package routes
 
 import (
 	"github.com/gorilla/mux"
+	"go-rest-modul/auth"
 	"go-rest-modul/handlers"
+	"go-rest-modul/repository"
+	"go-rest-modul/storage"
+	"net/http"
+	"strings"
 )
 
-func RegisterRoutes() *mux.Router {
+// Dependencies berisi semua repository dan layanan yang dibutuhkan handler.
+type Dependencies struct {
+	Recipes       repository.RecipeRepository
+	Categories    repository.CategoryRepository
+	Tags          repository.TagRepository
+	Ingredients   repository.IngredientRepository
+	ShoppingLists repository.ShoppingListRepository
+	MealPlans     repository.MealPlanRepository
+	Nutrients     repository.NutrientRepository
+	Catalog       repository.CatalogRepository
+	Users         repository.UserRepository
+	Reviews       repository.ReviewRepository
+	Favorites     repository.FavoriteRepository
+	Collections   repository.CollectionRepository
+	Images        repository.RecipeImageRepository
+	Revisions     repository.RecipeRevisionRepository
+	Storage       storage.Storage
+	Tokens        *auth.Tokens
+	// PublicReads mengizinkan request GET tanpa login
+	PublicReads bool
+	// DefaultRole adalah role untuk user yang baru mendaftar
+	DefaultRole string
+	// MaxUploadSize (byte) dan MaxImagePixels membatasi gambar yang diunggah
+	MaxUploadSize  int
+	MaxImagePixels int
+	// Media melayani file storage lokal pada MediaPath; nil jika file
+	// disimpan di luar server ini
+	Media     http.Handler
+	MediaPath string
+}
+
+func RegisterRoutes(deps Dependencies) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 
+	recipeHandler := handlers.NewRecipeHandler(deps.Recipes, deps.Categories, deps.Nutrients)
+	categoryHandler := handlers.NewCategoryHandler(deps.Categories)
+	tagHandler := handlers.NewTagHandler(deps.Tags)
+	ingredientHandler := handlers.NewIngredientHandler(deps.Ingredients, deps.Nutrients)
+	shoppingListHandler := handlers.NewShoppingListHandler(deps.ShoppingLists)
+	mealPlanHandler := handlers.NewMealPlanHandler(deps.MealPlans, deps.ShoppingLists)
+	catalogHandler := handlers.NewCatalogHandler(deps.Catalog)
+	authHandler := handlers.NewAuthHandler(deps.Users, deps.Tokens, deps.DefaultRole)
+	userHandler := handlers.NewUserHandler(deps.Users)
+	reviewHandler := handlers.NewReviewHandler(deps.Reviews)
+	collectionHandler := handlers.NewCollectionHandler(deps.Favorites, deps.Collections)
+	revisionHandler := handlers.NewRevisionHandler(deps.Recipes, deps.Revisions)
+	imageHandler := handlers.NewImageHandler(deps.Recipes, deps.Images, deps.Storage, deps.MaxUploadSize, deps.MaxImagePixels)
+
+	// Token dibaca untuk semua route; route yang wajib login diberi Protect
+	authenticator := handlers.NewAuthenticator(deps.Tokens, deps.PublicReads)
+	router.Use(authenticator.Authenticate)
+
+	// can mewajibkan permission untuk satu route, signedIn cukup login
+	can := func(permission auth.Permission, handler http.HandlerFunc) http.Handler {
+		return authenticator.Require(permission)(handler)
+	}
+	signedIn := func(handler http.HandlerFunc) http.Handler {
+		return authenticator.RequireUser(handler)
+	}
+
+	// Auth Routes
+	authRoutes := router.PathPrefix("/api/auth").Subrouter()
+	authRoutes.HandleFunc("/register", authHandler.Register).Methods("POST")
+	authRoutes.HandleFunc("/login", authHandler.Login).Methods("POST")
+	authRoutes.HandleFunc("/refresh", authHandler.Refresh).Methods("POST")
+	authRoutes.HandleFunc("/logout", authHandler.Logout).Methods("POST")
+	authRoutes.Handle("/me", signedIn(authHandler.Me)).Methods("GET")
+
 	// Recipe Routes
 	recipe := router.PathPrefix("/api/recipe").Subrouter()
-	recipe.HandleFunc("", handlers.AddRecipeHandler).Methods("POST")
-	recipe.HandleFunc("/{id}", handlers.ReadbyIDHandler).Methods("GET")
-	recipe.HandleFunc("/{id}", handlers.UpdateRecipeHandler).Methods("PUT")
-	recipe.HandleFunc("/{id}", handlers.DeleteRecipeHandler).Methods("DELETE")
+	recipe.Use(authenticator.Protect)
+	recipe.Handle("", can(auth.PermRecipeWrite, recipeHandler.AddRecipeHandler)).Methods("POST")
+	recipe.HandleFunc("/{id}", recipeHandler.ReadbyIDHandler).Methods("GET")
+	recipe.Handle("/{id}", can(auth.PermRecipeWrite, recipeHandler.UpdateRecipeHandler)).Methods("PUT")
+	recipe.Handle("/{id}", can(auth.PermRecipeWrite, recipeHandler.DeleteRecipeHandler)).Methods("DELETE")
+	recipe.HandleFunc("/{id}/scaled", recipeHandler.ScaleRecipeHandler).Methods("GET")
+	recipe.HandleFunc("/{id}/reviews", reviewHandler.GetRecipeReviews).Methods("GET")
+	recipe.Handle("/{id}/reviews", signedIn(reviewHandler.AddReview)).Methods("POST")
+	recipe.HandleFunc("/{id}/revisions", revisionHandler.GetRevisions).Methods("GET")
+	recipe.HandleFunc("/{id}/revisions/diff", revisionHandler.DiffRevisions).Methods("GET")
+	recipe.HandleFunc("/{id}/revisions/{number:[0-9]+}", revisionHandler.GetRevision).Methods("GET")
+	recipe.Handle("/{id}/revisions/{number:[0-9]+}/restore", can(auth.PermRecipeWrite, revisionHandler.RestoreRevision)).Methods("POST")
+	recipe.Handle("/{id}/image", can(auth.PermRecipeWrite, imageHandler.UploadRecipeImage)).Methods("POST")
+	recipe.Handle("/{id}/image", can(auth.PermRecipeWrite, imageHandler.DeleteRecipeImage)).Methods("DELETE")
+
+	// Review Routes, hanya penulis review (atau moderator untuk hapus)
+	review := router.PathPrefix("/api/review").Subrouter()
+	review.Use(authenticator.Protect)
+	review.Handle("/{id}", signedIn(reviewHandler.UpdateReview)).Methods("PUT")
+	review.Handle("/{id}", signedIn(reviewHandler.DeleteReview)).Methods("DELETE")
 
 	// Recipes Collection
 	recipes := router.PathPrefix("/api/recipes").Subrouter()
-	recipes.HandleFunc("", handlers.ReadAllHandler).Methods("GET")
-	recipes.HandleFunc("/search", handlers.SearchRecipeHandler).Methods("GET")
-	recipes.HandleFunc("/filter", handlers.FilterRecipesHandler).Methods("GET")
-	recipes.HandleFunc("/category/{category_id}", handlers.FilterByCategoryHandler).Methods("GET")
+	recipes.Use(authenticator.Protect)
+	recipes.HandleFunc("", recipeHandler.ReadAllHandler).Methods("GET")
+	recipes.HandleFunc("/search", recipeHandler.SearchRecipeHandler).Methods("GET")
+	recipes.Handle("/mine", signedIn(recipeHandler.MyRecipesHandler)).Methods("GET")
+	recipes.HandleFunc("/filter", recipeHandler.FilterRecipesHandler).Methods("GET")
+	recipes.HandleFunc("/cookable", recipeHandler.CookableRecipesHandler).Methods("GET")
+	recipes.Handle("/import", can(auth.PermRecipeWrite, recipeHandler.ImportRecipeHandler)).Methods("POST")
+	recipes.HandleFunc("/category/{category_id}", recipeHandler.FilterByCategoryHandler).Methods("GET")
 
 	category := router.PathPrefix("/api/category").Subrouter()
-	category.HandleFunc("/{id}", handlers.GetCategorybyId).Methods("GET")
-	category.HandleFunc("/{id}", handlers.UpdateCategory).Methods("PUT")
-	category.HandleFunc("/{id}", handlers.DeleteCategory).Methods("DELETE")
-	category.HandleFunc("", handlers.CreateCategory).Methods("POST")
+	category.Use(authenticator.Protect)
+	category.HandleFunc("/{id}", categoryHandler.GetCategorybyId).Methods("GET")
+	category.Handle("/{id}", can(auth.PermCategoryWrite, categoryHandler.UpdateCategory)).Methods("PUT")
+	category.Handle("/{id}", can(auth.PermCategoryDelete, categoryHandler.DeleteCategory)).Methods("DELETE")
+	category.Handle("", can(auth.PermCategoryWrite, categoryHandler.CreateCategory)).Methods("POST")
 
 	//routes untuk Category Functionality
 	categories := router.PathPrefix("/api/categories").Subrouter()
-	categories.HandleFunc("", handlers.GetAllCategory).Methods("GET")
+	categories.Use(authenticator.Protect)
+	categories.HandleFunc("", categoryHandler.GetAllCategory).Methods("GET")
+	categories.HandleFunc("/tree", categoryHandler.GetCategoryTree).Methods("GET")
+
+	// Tag Routes
+	tag := router.PathPrefix("/api/tag").Subrouter()
+	tag.Use(authenticator.Protect)
+	tag.HandleFunc("/{id}", tagHandler.GetTagbyId).Methods("GET")
+	tag.Handle("/{id}", can(auth.PermTagWrite, tagHandler.UpdateTag)).Methods("PUT")
+	tag.Handle("/{id}", can(auth.PermTagDelete, tagHandler.DeleteTag)).Methods("DELETE")
+	tag.Handle("", can(auth.PermTagWrite, tagHandler.CreateTag)).Methods("POST")
+
+	tags := router.PathPrefix("/api/tags").Subrouter()
+	tags.Use(authenticator.Protect)
+	tags.HandleFunc("", tagHandler.GetAllTag).Methods("GET")
+
+	// Ingredient Routes
+	ingredient := router.PathPrefix("/api/ingredient").Subrouter()
+	ingredient.Use(authenticator.Protect)
+	ingredient.HandleFunc("/{id}", ingredientHandler.GetIngredientbyId).Methods("GET")
+	ingredient.Handle("/{id}", can(auth.PermIngredientWrite, ingredientHandler.UpdateIngredient)).Methods("PUT")
+	ingredient.Handle("/{id}", can(auth.PermIngredientDelete, ingredientHandler.DeleteIngredient)).Methods("DELETE")
+	ingredient.HandleFunc("/{id}/recipes", ingredientHandler.GetRecipesByIngredient).Methods("GET")
+	ingredient.HandleFunc("/{id}/nutrients", ingredientHandler.GetIngredientNutrients).Methods("GET")
+	ingredient.Handle("/{id}/nutrients", can(auth.PermIngredientWrite, ingredientHandler.UpdateIngredientNutrients)).Methods("PUT")
+	ingredient.Handle("", can(auth.PermIngredientWrite, ingredientHandler.CreateIngredient)).Methods("POST")
+
+	// Ingredients Collection
+	ingredients := router.PathPrefix("/api/ingredients").Subrouter()
+	ingredients.Use(authenticator.Protect)
+	ingredients.HandleFunc("", ingredientHandler.GetAllIngredient).Methods("GET")
+
+	// Shopping List dan Meal Plan, selalu milik user yang login
+	shoppingList := router.PathPrefix("/api/shopping-list").Subrouter()
+	shoppingList.Use(authenticator.RequireUser)
+	shoppingList.HandleFunc("/{id}", shoppingListHandler.GetShoppingListbyId).Methods("GET")
+	shoppingList.HandleFunc("/{id}", shoppingListHandler.DeleteShoppingList).Methods("DELETE")
+	shoppingList.HandleFunc("/{id}/item/{item_id}", shoppingListHandler.CheckShoppingListItem).Methods("PUT")
+	shoppingList.HandleFunc("", shoppingListHandler.CreateShoppingList).Methods("POST")
+
+	shoppingLists := router.PathPrefix("/api/shopping-lists").Subrouter()
+	shoppingLists.Use(authenticator.RequireUser)
+	shoppingLists.HandleFunc("", shoppingListHandler.GetAllShoppingList).Methods("GET")
+
+	mealPlan := router.PathPrefix("/api/meal-plan").Subrouter()
+	mealPlan.Use(authenticator.RequireUser)
+	mealPlan.HandleFunc("/{id}", mealPlanHandler.GetMealPlanbyId).Methods("GET")
+	mealPlan.HandleFunc("/{id}", mealPlanHandler.UpdateMealPlan).Methods("PUT")
+	mealPlan.HandleFunc("/{id}", mealPlanHandler.DeleteMealPlan).Methods("DELETE")
+	mealPlan.HandleFunc("/{id}/week", mealPlanHandler.GetMealPlanWeek).Methods("GET")
+	mealPlan.HandleFunc("/{id}/entry", mealPlanHandler.AddMealPlanEntry).Methods("POST")
+	mealPlan.HandleFunc("/{id}/entry/{entry_id}", mealPlanHandler.DeleteMealPlanEntry).Methods("DELETE")
+	mealPlan.HandleFunc("/{id}/shopping-list", mealPlanHandler.CreateMealPlanShoppingList).Methods("POST")
+	mealPlan.HandleFunc("", mealPlanHandler.CreateMealPlan).Methods("POST")
+
+	mealPlans := router.PathPrefix("/api/meal-plans").Subrouter()
+	mealPlans.Use(authenticator.RequireUser)
+	mealPlans.HandleFunc("", mealPlanHandler.GetAllMealPlan).Methods("GET")
+
+	// Favorite dan Collection, selalu milik user yang login
+	favorites := router.PathPrefix("/api/favorites").Subrouter()
+	favorites.Use(authenticator.RequireUser)
+	favorites.HandleFunc("", collectionHandler.GetFavorites).Methods("GET")
+	favorites.HandleFunc("/{recipe_id}", collectionHandler.AddFavorite).Methods("PUT")
+	favorites.HandleFunc("/{recipe_id}", collectionHandler.RemoveFavorite).Methods("DELETE")
+
+	collection := router.PathPrefix("/api/collection").Subrouter()
+	collection.Use(authenticator.RequireUser)
+	collection.HandleFunc("/{id}", collectionHandler.GetCollectionbyId).Methods("GET")
+	collection.HandleFunc("/{id}", collectionHandler.UpdateCollection).Methods("PUT")
+	collection.HandleFunc("/{id}", collectionHandler.DeleteCollection).Methods("DELETE")
+	collection.HandleFunc("/{id}/recipes", collectionHandler.AddCollectionRecipe).Methods("POST")
+	collection.HandleFunc("/{id}/recipes/{recipe_id}", collectionHandler.RemoveCollectionRecipe).Methods("DELETE")
+	collection.HandleFunc("/{id}/order", collectionHandler.ReorderCollection).Methods("PUT")
+	collection.HandleFunc("/{id}/share", collectionHandler.ShareCollection).Methods("POST")
+	collection.HandleFunc("/{id}/share", collectionHandler.UnshareCollection).Methods("DELETE")
+	collection.HandleFunc("", collectionHandler.CreateCollection).Methods("POST")
+
+	collections := router.PathPrefix("/api/collections").Subrouter()
+	collections.Use(authenticator.RequireUser)
+	collections.HandleFunc("", collectionHandler.GetAllCollection).Methods("GET")
+
+	// Link share collection bisa dibuka tanpa login
+	shared := router.PathPrefix("/api/shared").Subrouter()
+	shared.HandleFunc("/collection/{token}", collectionHandler.GetSharedCollection).Methods("GET")
+
+	// User Management, hanya untuk admin
+	user := router.PathPrefix("/api/user").Subrouter()
+	user.Handle("/{id}", can(auth.PermUserManage, userHandler.GetUserbyId)).Methods("GET")
+	user.Handle("/{id}", can(auth.PermUserManage, userHandler.DeleteUser)).Methods("DELETE")
+	user.Handle("/{id}/role", can(auth.PermUserManage, userHandler.UpdateUserRole)).Methods("PUT")
+
+	users := router.PathPrefix("/api/users").Subrouter()
+	users.Handle("", can(auth.PermUserManage, userHandler.GetAllUser)).Methods("GET")
+
+	// Catalog Import/Export
+	catalog := router.PathPrefix("/api/catalog").Subrouter()
+	catalog.Use(authenticator.Protect)
+	catalog.Handle("/import", can(auth.PermCatalogImport, catalogHandler.ImportCatalogHandler)).Methods("POST")
+	catalog.HandleFunc("/export", catalogHandler.ExportCatalogHandler).Methods("GET")
+
+	// File gambar dari storage lokal, bisa dibuka tanpa login
+	if deps.Media != nil {
+		prefix := strings.TrimSuffix(deps.MediaPath, "/") + "/"
+		router.PathPrefix(prefix).Handler(http.StripPrefix(prefix, deps.Media)).Methods("GET", "HEAD")
+	}
 
 	return router
 }
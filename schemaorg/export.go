@@ -0,0 +1,91 @@
+package schemaorg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Document adalah schema.org/Recipe dalam bentuk JSON-LD.
+type Document struct {
+	Context            string      `json:"@context"`
+	Type               string      `json:"@type"`
+	Name               string      `json:"name"`
+	Description        string      `json:"description,omitempty"`
+	Image              string      `json:"image,omitempty"`
+	PrepTime           string      `json:"prepTime,omitempty"`
+	CookTime           string      `json:"cookTime,omitempty"`
+	TotalTime          string      `json:"totalTime,omitempty"`
+	RecipeYield        string      `json:"recipeYield,omitempty"`
+	RecipeCategory     string      `json:"recipeCategory,omitempty"`
+	RecipeIngredient   []string    `json:"recipeIngredient"`
+	RecipeInstructions []HowToStep `json:"recipeInstructions"`
+	DatePublished      string      `json:"datePublished,omitempty"`
+	DateModified       string      `json:"dateModified,omitempty"`
+}
+
+type HowToStep struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+// Document mengubah Recipe menjadi dokumen JSON-LD. Tanggal tidak diisi;
+// pemanggil bisa menambahkannya sendiri.
+func (r Recipe) Document() Document {
+	doc := Document{
+		Context:            "https://schema.org",
+		Type:               "Recipe",
+		Name:               r.Name,
+		Description:        r.Description,
+		Image:              r.Image,
+		PrepTime:           FormatDuration(r.PrepTime),
+		CookTime:           FormatDuration(r.CookTime),
+		RecipeCategory:     r.Category,
+		RecipeIngredient:   append([]string{}, r.Ingredients...),
+		RecipeInstructions: []HowToStep{},
+	}
+	if r.PrepTime > 0 && r.CookTime > 0 {
+		doc.TotalTime = FormatDuration(r.PrepTime + r.CookTime)
+	}
+	if r.Yield > 0 {
+		doc.RecipeYield = strconv.Itoa(r.Yield)
+	}
+	for _, step := range r.Instructions {
+		doc.RecipeInstructions = append(doc.RecipeInstructions, HowToStep{Type: "HowToStep", Text: step})
+	}
+	return doc
+}
+
+// FormatDuration menulis menit sebagai durasi ISO-8601, misal 65 menjadi
+// "PT1H5M". Nilai 0 atau negatif menghasilkan string kosong.
+func FormatDuration(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	s := "PT"
+	if hours := minutes / 60; hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if rest := minutes % 60; rest > 0 {
+		s += fmt.Sprintf("%dM", rest)
+	}
+	return s
+}
+
+// SplitInstructions memecah teks instruksi per baris menjadi langkah dan
+// membuang penomoran seperti "1." atau "2)" di awal baris.
+func SplitInstructions(text string) []string {
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.IndexAny(line, ".)"); i > 0 {
+			if _, err := strconv.Atoi(line[:i]); err == nil {
+				line = strings.TrimSpace(line[i+1:])
+			}
+		}
+		if line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
@@ -0,0 +1,279 @@
+// Package schemaorg membaca data schema.org/Recipe dalam format JSON-LD,
+// baik dokumen JSON langsung maupun HTML yang memuat tag
+// <script type="application/ld+json">.
+package schemaorg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoRecipe        = errors.New("no schema.org Recipe found")
+	ErrInvalidDuration = errors.New("invalid ISO-8601 duration")
+)
+
+// Recipe adalah field schema.org/Recipe yang dipakai aplikasi ini.
+type Recipe struct {
+	Name         string
+	Description  string
+	Ingredients  []string
+	Instructions []string
+	// PrepTime dan CookTime dalam menit
+	PrepTime int
+	CookTime int
+	// Yield adalah jumlah porsi, 0 jika recipeYield tidak berisi angka
+	Yield    int
+	Image    string
+	Category string
+}
+
+var ldJSONScript = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+// Parse mengembalikan semua Recipe pada dokumen. data boleh berupa JSON-LD
+// atau HTML; untuk HTML setiap blok ld+json dibaca, dan blok yang bukan JSON
+// valid dilewati.
+func Parse(data []byte) ([]Recipe, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+
+	var blocks [][]byte
+	if len(data) > 0 && data[0] == '<' {
+		for _, match := range ldJSONScript.FindAllSubmatch(data, -1) {
+			blocks = append(blocks, match[1])
+		}
+	} else {
+		blocks = [][]byte{data}
+	}
+
+	var recipes []Recipe
+	var lastErr error
+	for _, block := range blocks {
+		var doc interface{}
+		if err := json.Unmarshal(bytes.TrimSpace(block), &doc); err != nil {
+			lastErr = err
+			continue
+		}
+		for _, node := range findRecipes(doc) {
+			recipe, err := mapRecipe(node)
+			if err != nil {
+				return nil, err
+			}
+			recipes = append(recipes, recipe)
+		}
+	}
+
+	if len(recipes) == 0 {
+		// Dokumen JSON tunggal yang rusak lebih berguna dilaporkan apa adanya
+		if len(blocks) == 1 && lastErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoRecipe, lastErr)
+		}
+		return nil, ErrNoRecipe
+	}
+	return recipes, nil
+}
+
+// findRecipes mencari node bertipe Recipe, termasuk di dalam array, @graph
+// dan mainEntity.
+func findRecipes(node interface{}) []map[string]interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		var found []map[string]interface{}
+		for _, item := range v {
+			found = append(found, findRecipes(item)...)
+		}
+		return found
+	case map[string]interface{}:
+		if isRecipe(v["@type"]) {
+			return []map[string]interface{}{v}
+		}
+		var found []map[string]interface{}
+		for _, key := range []string{"@graph", "mainEntity"} {
+			if child, ok := v[key]; ok {
+				found = append(found, findRecipes(child)...)
+			}
+		}
+		return found
+	}
+	return nil
+}
+
+func isRecipe(t interface{}) bool {
+	switch v := t.(type) {
+	case string:
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "http://schema.org/"), "https://schema.org/")
+		return strings.TrimPrefix(v, "schema:") == "Recipe"
+	case []interface{}:
+		for _, item := range v {
+			if isRecipe(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mapRecipe(node map[string]interface{}) (Recipe, error) {
+	recipe := Recipe{
+		Name:         text(node["name"]),
+		Description:  text(node["description"]),
+		Ingredients:  texts(first(node["recipeIngredient"], node["ingredients"])),
+		Instructions: instructions(node["recipeInstructions"]),
+		Yield:        yield(node["recipeYield"]),
+		Image:        image(node["image"]),
+	}
+	if categories := texts(node["recipeCategory"]); len(categories) > 0 {
+		recipe.Category = categories[0]
+	}
+
+	var err error
+	if recipe.PrepTime, err = minutes(node["prepTime"]); err != nil {
+		return recipe, fmt.Errorf("prepTime: %w", err)
+	}
+	if recipe.CookTime, err = minutes(node["cookTime"]); err != nil {
+		return recipe, fmt.Errorf("cookTime: %w", err)
+	}
+	return recipe, nil
+}
+
+func first(values ...interface{}) interface{} {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// text membaca nilai teks. Entity HTML seperti &amp; ikut diterjemahkan.
+func text(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		return text(first(v["@value"], v["text"], v["name"]))
+	case []interface{}:
+		if len(v) > 0 {
+			return text(v[0])
+		}
+	}
+	return ""
+}
+
+// texts membaca nilai tunggal maupun array sebagai daftar teks tidak kosong.
+func texts(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	var result []string
+	for _, item := range items {
+		if s := text(item); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// instructions meratakan string, daftar string, HowToStep dan HowToSection
+// menjadi daftar langkah.
+func instructions(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		var steps []string
+		for _, line := range strings.Split(html.UnescapeString(v), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				steps = append(steps, line)
+			}
+		}
+		return steps
+	case []interface{}:
+		var steps []string
+		for _, item := range v {
+			steps = append(steps, instructions(item)...)
+		}
+		return steps
+	case map[string]interface{}:
+		if elements, ok := v["itemListElement"]; ok {
+			return instructions(elements)
+		}
+		if step := text(first(v["text"], v["name"])); step != "" {
+			return []string{step}
+		}
+	}
+	return nil
+}
+
+// yield mengambil angka pertama dari recipeYield, misal "4 servings".
+func yield(v interface{}) int {
+	for _, s := range texts(v) {
+		for _, field := range strings.Fields(s) {
+			if n, err := strconv.Atoi(field); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+func image(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, item := range v {
+			if url := image(item); url != "" {
+				return url
+			}
+		}
+	case map[string]interface{}:
+		return image(first(v["url"], v["contentUrl"], v["@id"]))
+	}
+	return ""
+}
+
+func minutes(v interface{}) (int, error) {
+	s := text(v)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return int(d.Round(time.Minute) / time.Minute), nil
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration membaca durasi ISO-8601 seperti "PT1H30M" atau "P1DT2H".
+// Tahun dan bulan tidak didukung karena panjangnya tidak tetap.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	match := isoDuration.FindStringSubmatch(s)
+	if match == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		total += time.Duration(value * float64(unit))
+	}
+	return total, nil
+}
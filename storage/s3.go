@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go-rest-modul/config"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3 menyimpan object di bucket yang kompatibel dengan API S3 (AWS S3, MinIO,
+// Cloudflare R2, dan sejenisnya). Request ditandatangani dengan AWS
+// Signature Version 4 tanpa SDK. Dengan PathStyle dan endpoint lokal, client
+// ini bisa diarahkan ke MinIO atau stand-in S3 lain untuk pengujian.
+type S3 struct {
+	cfg       config.S3Config
+	endpoint  *url.URL
+	publicURL string
+	client    *http.Client
+}
+
+// NewS3 membuat client S3. Jika publicURL kosong, URL object dibentuk dari
+// endpoint dan bucket; isi publicURL untuk memakai CDN.
+func NewS3(cfg config.S3Config, publicURL string) (*S3, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage.s3.endpoint: %w", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage.s3.endpoint %q must be an absolute URL", cfg.Endpoint)
+	}
+	return &S3{
+		cfg:       cfg,
+		endpoint:  endpoint,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	if err := s.do(ctx, http.MethodPut, key, contentType, data); err != nil {
+		return "", err
+	}
+	if s.publicURL != "" {
+		return joinURL(s.publicURL, key), nil
+	}
+	return s.objectURL(key), nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	return s.do(ctx, http.MethodDelete, key, "", nil)
+}
+
+// objectURL menyusun URL object, path-style (endpoint/bucket/key) atau
+// virtual-hosted (bucket.endpoint/key).
+func (s *S3) objectURL(key string) string {
+	base := *s.endpoint
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	base.RawPath = ""
+	prefix := strings.TrimSuffix(base.String(), "/")
+	if s.cfg.PathStyle {
+		return prefix + "/" + escapePath(s.cfg.Bucket) + "/" + escapePath(key)
+	}
+	base.Host = s.cfg.Bucket + "." + base.Host
+	return strings.TrimSuffix(base.String(), "/") + "/" + escapePath(key)
+}
+
+func (s *S3) do(ctx context.Context, method, key, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+	defer resp.Body.Close()
+
+	// DELETE pada object yang tidak ada tetap dianggap berhasil
+	if resp.StatusCode/100 == 2 || (method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, bytes.TrimSpace(detail))
+}
+
+// sign menambahkan header Authorization AWS Signature Version 4.
+func (s *S3) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := amzDate[:8]
+	payloadHash := hashHex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signedHeaders = "content-type;" + signedHeaders
+		canonicalHeaders = "content-type:" + contentType + "\n" + canonicalHeaders
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), day)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath meng-encode setiap karakter selain huruf, angka, "-", "_", ".",
+// "~" dan "/", sesuai aturan URI encoding SigV4.
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+// Package storage menyimpan file unggahan seperti gambar recipe, baik di
+// filesystem lokal maupun object storage yang kompatibel dengan S3.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-rest-modul/config"
+	"path"
+	"strings"
+)
+
+// ErrInvalidKey dikembalikan jika key bukan path relatif yang bersih.
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage menyimpan object berdasarkan key berbentuk path relatif, misalnya
+// "recipes/12/5f1c9a/thumb.jpg".
+type Storage interface {
+	// Put menyimpan data dan mengembalikan URL publik object tersebut.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete menghapus object. Key yang tidak ada tidak dianggap error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New membuat Storage sesuai cfg.Driver.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case config.StorageLocal:
+		return NewLocal(cfg.LocalDir, cfg.PublicURL)
+	case config.StorageS3:
+		return NewS3(cfg.S3, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// validKey menolak key kosong, absolut, atau yang keluar dari root lewat "..".
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) ||
+		path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// joinURL menggabungkan URL dasar dan key tanpa garis miring ganda.
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
@@ -0,0 +1,144 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Amount adalah jumlah bahan. Untuk jumlah tunggal Min sama dengan Max,
+// sedangkan rentang seperti "2-3" disimpan sebagai Min 2 dan Max 3.
+type Amount struct {
+	Min float64
+	Max float64
+}
+
+func Single(value float64) Amount {
+	return Amount{Min: value, Max: value}
+}
+
+func (a Amount) IsRange() bool {
+	return a.Min != a.Max
+}
+
+// Scale mengalikan kedua ujung rentang dengan factor.
+func (a Amount) Scale(factor float64) Amount {
+	return Amount{Min: a.Min * factor, Max: a.Max * factor}
+}
+
+// Add menjumlahkan dua amount, termasuk rentang (ujung ke ujung).
+func (a Amount) Add(b Amount) Amount {
+	return Amount{Min: a.Min + b.Min, Max: a.Max + b.Max}
+}
+
+var unicodeFractions = map[rune]float64{
+	'¼': 1.0 / 4, '½': 1.0 / 2, '¾': 3.0 / 4,
+	'⅓': 1.0 / 3, '⅔': 2.0 / 3,
+	'⅕': 1.0 / 5, '⅖': 2.0 / 5, '⅗': 3.0 / 5, '⅘': 4.0 / 5,
+	'⅙': 1.0 / 6, '⅚': 5.0 / 6,
+	'⅛': 1.0 / 8, '⅜': 3.0 / 8, '⅝': 5.0 / 8, '⅞': 7.0 / 8,
+}
+
+// ParseAmount membaca jumlah seperti "2", "1.5", "1,5", "1/2", "1 1/2",
+// "1½", serta rentang "2-3", "2–3" atau "2 to 3".
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{}, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	for _, separator := range []string{" to ", " sampai ", "–", "—", "-"} {
+		// "-" di awal adalah tanda minus, bukan rentang
+		if i := strings.Index(s, separator); i > 0 {
+			min, err := parseNumber(s[:i])
+			if err != nil {
+				return Amount{}, err
+			}
+			max, err := parseNumber(s[i+len(separator):])
+			if err != nil {
+				return Amount{}, err
+			}
+			if max < min {
+				min, max = max, min
+			}
+			return Amount{Min: min, Max: max}, nil
+		}
+	}
+
+	value, err := parseNumber(s)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Single(value), nil
+}
+
+// parseNumber membaca bilangan bulat, desimal, pecahan, atau bilangan campuran.
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	// Pisahkan pecahan unicode yang menempel, misalnya "1½" menjadi "1 ½"
+	var b strings.Builder
+	for _, r := range s {
+		if _, ok := unicodeFractions[r]; ok {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	fields := strings.Fields(b.String())
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	var total float64
+	for i, field := range fields {
+		value, isFraction, err := parseTerm(field)
+		if err != nil {
+			return 0, err
+		}
+		// Bilangan campuran hanya boleh "bulat pecahan"
+		if i == 1 && !isFraction {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		total += value
+	}
+	if total < 0 {
+		return 0, fmt.Errorf("%w: negative %q", ErrInvalidAmount, s)
+	}
+	if !isFinite(total) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return total, nil
+}
+
+func parseTerm(s string) (value float64, isFraction bool, err error) {
+	if runes := []rune(s); len(runes) == 1 {
+		if v, ok := unicodeFractions[runes[0]]; ok {
+			return v, true, nil
+		}
+	}
+
+	if numerator, denominator, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.ParseFloat(numerator, 64)
+		d, err2 := strconv.ParseFloat(denominator, 64)
+		if err1 != nil || err2 != nil || d == 0 || !isFinite(n) || !isFinite(d) || !isFinite(n/d) {
+			return 0, false, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		return n / d, true, nil
+	}
+
+	// Koma desimal ala Indonesia: "1,5"
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil || !isFinite(v) {
+		return 0, false, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return v, false, nil
+}
+
+// isFinite menolak NaN dan Inf yang ikut diterima strconv.ParseFloat.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,46 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Amount
+	}{
+		{"2", Single(2)},
+		{"1.5", Single(1.5)},
+		{"1,5", Single(1.5)},
+		{"1/2", Single(0.5)},
+		{"1 1/2", Single(1.5)},
+		{"1½", Single(1.5)},
+		{"¾", Single(0.75)},
+		{"2-3", Amount{Min: 2, Max: 3}},
+		{"3 to 2", Amount{Min: 2, Max: 3}},
+		{"1 sampai 1/2", Amount{Min: 0.5, Max: 1}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.input)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountInvalid(t *testing.T) {
+	for _, input := range []string{
+		"", "abc", "-1", "1/0", "1 2", "1 2 3",
+		"NaN", "Inf", "NaN/1", "1/NaN", "Inf/2", "2/Inf", "1-NaN/1", "1 NaN/1",
+		"1e308/1e-308", "1e308 1e308/1",
+	} {
+		if got, err := ParseAmount(input); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("ParseAmount(%q) = %+v, %v, want ErrInvalidAmount", input, got, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Quantity adalah jumlah beserta satuannya.
+type Quantity struct {
+	Amount Amount
+	Unit   Unit
+}
+
+// Parse membaca pasangan amount dan unit seperti yang disimpan pada
+// RecipeIngredient.
+func Parse(amount, unit string) (Quantity, error) {
+	a, err := ParseAmount(amount)
+	if err != nil {
+		return Quantity{}, err
+	}
+	u, err := Lookup(unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Amount: a, Unit: u}, nil
+}
+
+// Base mengembalikan amount dalam satuan dasar dimensinya (ml, g, atau buah).
+func (q Quantity) Base() Amount {
+	return q.Amount.Scale(q.Unit.Factor)
+}
+
+// Convert mengubah value dari satuan from ke satuan to. Konversi antara volume
+// dan massa membutuhkan density bahan dalam gram per mililiter; isi 0 jika
+// tidak diketahui.
+func Convert(value float64, from, to Unit, density float64) (float64, error) {
+	base := value * from.Factor
+	switch {
+	case from.Dimension == to.Dimension:
+	case from.Dimension == Volume && to.Dimension == Mass && density > 0:
+		base *= density
+	case from.Dimension == Mass && to.Dimension == Volume && density > 0:
+		base /= density
+	default:
+		return 0, fmt.Errorf("%w: %s to %s", ErrIncompatibleUnits, from.Dimension, to.Dimension)
+	}
+	return base / to.Factor, nil
+}
+
+// To mengubah quantity ke satuan lain.
+func (q Quantity) To(unit Unit, density float64) (Quantity, error) {
+	min, err := Convert(q.Amount.Min, q.Unit, unit, density)
+	if err != nil {
+		return Quantity{}, err
+	}
+	max, err := Convert(q.Amount.Max, q.Unit, unit, density)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Amount: Amount{Min: min, Max: max}, Unit: unit}, nil
+}
+
+// Scale mengalikan amount dengan factor tanpa mengganti satuan.
+func (q Quantity) Scale(factor float64) Quantity {
+	return Quantity{Amount: q.Amount.Scale(factor), Unit: q.Unit}
+}
+
+// InSystem mengubah quantity ke satuan paling mudah dibaca pada sistem yang
+// diminta dengan dimensi yang sama. Satuan hitungan dikembalikan apa adanya.
+func (q Quantity) InSystem(system System) Quantity {
+	base := q.Base()
+	unit, ok := BestUnit(base.Max, q.Unit.Dimension, system)
+	if !ok {
+		return q
+	}
+	return Quantity{Amount: base.Scale(1 / unit.Factor), Unit: unit}
+}
+
+// Normalize memilih satuan tampilan terbaik dalam sistem satuan itu sendiri,
+// misalnya 48 tsp menjadi 1 cup atau 1500 g menjadi 1.5 kg.
+func (q Quantity) Normalize() Quantity {
+	if q.Unit.System == Neutral {
+		return q
+	}
+	return q.InSystem(q.Unit.System)
+}
+
+// FormatAmount menulis amount sesuai kebiasaan sistemnya: pecahan dapur untuk
+// US customary dan hitungan, desimal untuk metrik.
+func FormatAmount(a Amount, system System) string {
+	format := formatFraction
+	if system == Metric {
+		format = formatDecimal
+	}
+	if !a.IsRange() {
+		return format(a.Min)
+	}
+	min, max := format(a.Min), format(a.Max)
+	if min == max {
+		return min
+	}
+	return min + "-" + max
+}
+
+// kitchenFractions adalah pecahan yang lazim di alat ukur dapur.
+var kitchenFractions = []struct {
+	value float64
+	text  string
+}{
+	{0, ""}, {1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"}, {3.0 / 8, "3/8"},
+	{1.0 / 2, "1/2"}, {5.0 / 8, "5/8"}, {2.0 / 3, "2/3"}, {3.0 / 4, "3/4"}, {7.0 / 8, "7/8"}, {1, ""},
+}
+
+// formatFraction membulatkan ke pecahan dapur terdekat, misal 1.49 menjadi "1 1/2".
+func formatFraction(v float64) string {
+	whole := math.Floor(v)
+	rest := v - whole
+
+	nearest := kitchenFractions[0]
+	for _, f := range kitchenFractions[1:] {
+		if math.Abs(rest-f.value) < math.Abs(rest-nearest.value) {
+			nearest = f
+		}
+	}
+	if nearest.value == 1 {
+		whole++
+	}
+
+	switch {
+	case whole == 0 && nearest.text == "":
+		// Terlalu kecil untuk pecahan 1/8, tampilkan desimal agar tidak jadi 0
+		if v > 0 {
+			return formatDecimal(v)
+		}
+		return "0"
+	case whole == 0:
+		return nearest.text
+	case nearest.text == "":
+		return strconv.FormatFloat(whole, 'f', 0, 64)
+	default:
+		return strconv.FormatFloat(whole, 'f', 0, 64) + " " + nearest.text
+	}
+}
+
+// formatDecimal memakai presisi yang makin kasar untuk nilai besar.
+func formatDecimal(v float64) string {
+	var s string
+	switch {
+	case v >= 100:
+		s = strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+	case v >= 10:
+		s = strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		s = strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Format menulis quantity menjadi pasangan amount dan unit siap simpan.
+func (q Quantity) Format() (amount, unit string) {
+	system := q.Unit.System
+	return FormatAmount(q.Amount, system), q.Unit.Name
+}
@@ -0,0 +1,52 @@
+package units
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitLine memecah baris bahan teks bebas seperti "2 cups flour, sifted"
+// menjadi amount ("2"), unit ("cups") dan name ("flour, sifted"). Baris tanpa
+// jumlah di depan, misal "salt to taste", dikembalikan sebagai name saja.
+func SplitLine(line string) (amount, unit, name string) {
+	fields := strings.Fields(line)
+
+	// Pisahkan angka yang menempel pada satuan, misal "200g" menjadi "200 g"
+	if len(fields) > 0 {
+		if i := strings.IndexFunc(fields[0], unicode.IsLetter); i > 0 {
+			if _, err := ParseAmount(fields[0][:i]); err == nil {
+				fields = append([]string{fields[0][:i], fields[0][i:]}, fields[1:]...)
+			}
+		}
+	}
+
+	// Cari awalan terpanjang (maksimal 3 kata) yang merupakan amount, agar
+	// "1 1/2" dan "2 to 3" terbaca utuh
+	taken := 0
+	for n := min(3, len(fields)); n > 0; n-- {
+		candidate := strings.Join(fields[:n], " ")
+		if _, err := ParseAmount(candidate); err == nil {
+			amount, taken = candidate, n
+			break
+		}
+	}
+	if taken == 0 {
+		return "", "", strings.TrimSpace(line)
+	}
+	fields = fields[taken:]
+
+	// Satuan boleh dua kata, misal "fl oz" atau "sendok makan"
+	for n := min(2, len(fields)); n > 0; n-- {
+		candidate := strings.Join(fields[:n], " ")
+		if _, err := Lookup(candidate); err == nil {
+			unit = strings.TrimSuffix(candidate, ".")
+			fields = fields[n:]
+			break
+		}
+	}
+
+	if len(fields) > 0 && strings.EqualFold(fields[0], "of") {
+		fields = fields[1:]
+	}
+	return amount, unit, strings.Join(fields, " ")
+}
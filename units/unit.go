@@ -0,0 +1,144 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrIncompatibleUnits = errors.New("incompatible units")
+	ErrUnknownSystem     = errors.New("unknown unit system")
+)
+
+type Dimension string
+
+const (
+	Volume Dimension = "volume"
+	Mass   Dimension = "mass"
+	Count  Dimension = "count"
+)
+
+type System string
+
+const (
+	Metric      System = "metric"
+	USCustomary System = "us"
+	// Neutral dipakai untuk satuan hitungan yang sama di semua sistem
+	Neutral System = ""
+)
+
+// ParseSystem membaca nama sistem satuan dari parameter request.
+func ParseSystem(s string) (System, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "metric", "si":
+		return Metric, nil
+	case "us", "imperial", "us_customary":
+		return USCustomary, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownSystem, s)
+	}
+}
+
+// Unit adalah satuan ukur. Factor mengubah nilai ke satuan dasar dimensinya:
+// mililiter untuk volume, gram untuk massa, dan buah untuk hitungan.
+type Unit struct {
+	Name      string
+	Dimension Dimension
+	System    System
+	Factor    float64
+	// MinDisplay adalah nilai terkecil yang masih enak dibaca dalam satuan
+	// ini, dipakai saat memilih satuan tampilan (misal 1/4 cup, bukan 4 tbsp)
+	MinDisplay float64
+}
+
+// Satuan volume US diturunkan dari teaspoon dan satuan massa US dari ounce,
+// sehingga kelipatannya tepat: 3 tsp = 1 tbsp, 16 tbsp = 1 cup, 16 oz = 1 lb.
+var (
+	Milliliter = Unit{Name: "ml", Dimension: Volume, System: Metric, Factor: 1, MinDisplay: 0}
+	Liter      = Unit{Name: "l", Dimension: Volume, System: Metric, Factor: 1000, MinDisplay: 1}
+	Teaspoon   = Unit{Name: "tsp", Dimension: Volume, System: USCustomary, Factor: 4.92892159375, MinDisplay: 0}
+	Tablespoon = Unit{Name: "tbsp", Dimension: Volume, System: USCustomary, Factor: 3 * Teaspoon.Factor, MinDisplay: 1}
+	FluidOunce = Unit{Name: "fl oz", Dimension: Volume, System: USCustomary, Factor: 2 * Tablespoon.Factor, MinDisplay: 1}
+	Cup        = Unit{Name: "cup", Dimension: Volume, System: USCustomary, Factor: 8 * FluidOunce.Factor, MinDisplay: 0.25}
+	Pint       = Unit{Name: "pint", Dimension: Volume, System: USCustomary, Factor: 2 * Cup.Factor, MinDisplay: 1}
+	Quart      = Unit{Name: "quart", Dimension: Volume, System: USCustomary, Factor: 2 * Pint.Factor, MinDisplay: 1}
+	Gallon     = Unit{Name: "gallon", Dimension: Volume, System: USCustomary, Factor: 4 * Quart.Factor, MinDisplay: 1}
+	Milligram  = Unit{Name: "mg", Dimension: Mass, System: Metric, Factor: 0.001, MinDisplay: 0}
+	Gram       = Unit{Name: "g", Dimension: Mass, System: Metric, Factor: 1, MinDisplay: 1}
+	Kilogram   = Unit{Name: "kg", Dimension: Mass, System: Metric, Factor: 1000, MinDisplay: 1}
+	Ounce      = Unit{Name: "oz", Dimension: Mass, System: USCustomary, Factor: 28.349523125, MinDisplay: 0}
+	Pound      = Unit{Name: "lb", Dimension: Mass, System: USCustomary, Factor: 16 * Ounce.Factor, MinDisplay: 1}
+	Piece      = Unit{Name: "", Dimension: Count, System: Neutral, Factor: 1}
+)
+
+// aliases memetakan penulisan satuan (huruf kecil) ke Unit. Termasuk
+// singkatan dapur Indonesia seperti sdt dan sdm.
+var aliases = map[string]Unit{
+	"ml": Milliliter, "milliliter": Milliliter, "milliliters": Milliliter, "millilitre": Milliliter, "millilitres": Milliliter, "cc": Milliliter,
+	"l": Liter, "liter": Liter, "liters": Liter, "litre": Liter, "litres": Liter,
+	"tsp": Teaspoon, "tsps": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon, "sdt": Teaspoon, "sendok teh": Teaspoon,
+	"tbsp": Tablespoon, "tbsps": Tablespoon, "tbs": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon, "sdm": Tablespoon, "sendok makan": Tablespoon,
+	"fl oz": FluidOunce, "fl. oz.": FluidOunce, "fluid ounce": FluidOunce, "fluid ounces": FluidOunce,
+	"cup": Cup, "cups": Cup, "c": Cup,
+	"pint": Pint, "pints": Pint, "pt": Pint,
+	"quart": Quart, "quarts": Quart, "qt": Quart,
+	"gallon": Gallon, "gallons": Gallon, "gal": Gallon,
+	"mg": Milligram, "milligram": Milligram, "milligrams": Milligram,
+	"g": Gram, "gr": Gram, "gram": Gram, "grams": Gram, "gramme": Gram, "grammes": Gram,
+	"kg": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram, "kilo": Kilogram,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound, "pounds": Pound,
+	"": Piece, "pc": Piece, "pcs": Piece, "piece": Piece, "pieces": Piece, "whole": Piece, "buah": Piece, "butir": Piece,
+}
+
+// Lookup mencari satuan berdasarkan nama atau singkatannya. "T" dan "t"
+// dibedakan (tablespoon dan teaspoon), selebihnya tidak peka huruf besar.
+func Lookup(name string) (Unit, error) {
+	name = strings.TrimSpace(name)
+	switch name {
+	case "T":
+		return Tablespoon, nil
+	case "t":
+		return Teaspoon, nil
+	}
+	if unit, ok := aliases[strings.ToLower(strings.TrimSuffix(name, "."))]; ok {
+		return unit, nil
+	}
+	return Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, name)
+}
+
+// displayUnits adalah satuan yang dipakai saat menampilkan hasil konversi,
+// diurutkan dari yang terkecil.
+var displayUnits = map[System]map[Dimension][]Unit{
+	Metric: {
+		Volume: {Milliliter, Liter},
+		Mass:   {Gram, Kilogram},
+	},
+	USCustomary: {
+		Volume: {Teaspoon, Tablespoon, Cup, Quart, Gallon},
+		Mass:   {Ounce, Pound},
+	},
+}
+
+// displayTolerance menyerap galat pembulatan float, misal 1/3 cup x 3 yang
+// bernilai sedikit di bawah 1 cup.
+const displayTolerance = 1e-9
+
+// BestUnit memilih satuan tampilan terbesar pada sistem dan dimensi yang sama
+// dengan base (nilai dalam satuan dasar) yang tetap di atas MinDisplay-nya.
+// Satuan gallon/quart/liter/kg hanya dipakai mulai satu satuan penuh.
+func BestUnit(base float64, dimension Dimension, system System) (Unit, bool) {
+	candidates := displayUnits[system][dimension]
+	if len(candidates) == 0 {
+		return Unit{}, false
+	}
+	best := candidates[0]
+	for _, unit := range candidates[1:] {
+		if base/unit.Factor >= unit.MinDisplay*(1-displayTolerance) {
+			best = unit
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,50 @@
+package units
+
+import "testing"
+
+func TestNormalizePromotesExactMultiples(t *testing.T) {
+	tests := []struct {
+		amount, unit string
+		scale        float64
+		wantAmount   string
+		wantUnit     string
+	}{
+		{"3", "tsp", 1, "1", "tbsp"},
+		{"48", "tsp", 1, "1", "cup"},
+		{"16", "tbsp", 1, "1", "cup"},
+		{"4", "cup", 1, "1", "quart"},
+		{"4", "quart", 1, "1", "gallon"},
+		{"16", "oz", 1, "1", "lb"},
+		{"1000", "g", 1, "1", "kg"},
+		{"1/3", "cup", 3, "1", "cup"},
+		{"1/3", "tsp", 9, "1", "tbsp"},
+		{"2", "tsp", 1, "2", "tsp"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.amount, tt.unit)
+		if err != nil {
+			t.Fatalf("Parse(%q, %q): %v", tt.amount, tt.unit, err)
+		}
+		amount, unit := q.Scale(tt.scale).Normalize().Format()
+		if amount != tt.wantAmount || unit != tt.wantUnit {
+			t.Errorf("%s %s x%g = %s %s, want %s %s", tt.amount, tt.unit, tt.scale, amount, unit, tt.wantAmount, tt.wantUnit)
+		}
+	}
+}
+
+func TestConvertUSVolume(t *testing.T) {
+	got, err := Convert(1, Cup, Milliliter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got < 236.58 || got > 236.59 {
+		t.Errorf("1 cup = %g ml, want about 236.588", got)
+	}
+	got, err = Convert(3, Teaspoon, Tablespoon, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1 {
+		t.Errorf("3 tsp = %v tbsp, want exactly 1", got)
+	}
+}